@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
+
 	"github.com/phuslu/log"
 	"template-manager-backend/internal/config"
 	"template-manager-backend/internal/handler"
 	"template-manager-backend/internal/repository"
 	"template-manager-backend/internal/usecase"
 	"template-manager-backend/pkg/database"
+	"template-manager-backend/pkg/gitea"
 	"template-manager-backend/pkg/github"
+	"template-manager-backend/pkg/gitlab"
+	"template-manager-backend/pkg/gitprovider"
+	"template-manager-backend/pkg/localgit"
 	appLogger "template-manager-backend/pkg/logger"
 
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	fiberlogger "github.com/gofiber/fiber/v2/middleware/logger"
 )
@@ -33,17 +40,71 @@ func main() {
 	// Inicializar repositórios
 	templateRepo := repository.NewTemplateRepository(db)
 	projectRepo := repository.NewProjectRepository(db)
-
-	// Inicializar serviços
-	gitService := github.NewGitService(cfg.GitHubToken, cfg.GitHubUsername)
+	jobRepo := repository.NewJobRepository(db)
+	stepRepo := repository.NewProjectStepRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	logRepo := repository.NewLogRepository(db)
+
+	// Inicializar provedores Git
+	providers := gitprovider.NewRegistry(cfg.GitProvider)
+	githubService, err := github.NewGitService(cfg.GitHubToken, cfg.GitHubUsername, cfg.GitHubAPIURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure GitHub provider")
+	}
+	providers.Register(github.ProviderName, githubService)
+	if cfg.GitLabToken != "" {
+		providers.Register(gitlab.ProviderName, gitlab.NewGitService(gitlab.Config{
+			BaseURL:       cfg.GitLabURL,
+			Token:         cfg.GitLabToken,
+			Namespace:     cfg.GitLabNamespace,
+			Visibility:    cfg.GitLabVisibility,
+			DefaultBranch: cfg.GitLabDefaultBranch,
+		}))
+	}
+	if cfg.GiteaURL != "" && cfg.GiteaToken != "" {
+		providers.Register(gitea.ProviderName, gitea.NewGitService(gitea.Config{
+			BaseURL:       cfg.GiteaURL,
+			Token:         cfg.GiteaToken,
+			Owner:         cfg.GiteaOwner,
+			Username:      cfg.GiteaUsername,
+			Private:       cfg.GiteaPrivate,
+			DefaultBranch: cfg.GiteaDefaultBranch,
+		}))
+	}
+	if cfg.LocalReposDir != "" {
+		localService, err := localgit.NewGitService(cfg.LocalReposDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to configure local git provider")
+		}
+		providers.Register(localgit.ProviderName, localService)
+	}
+	if _, err := providers.Get(""); err != nil {
+		log.Fatal().Err(err).Msg("Default git provider is not available")
+	}
 
 	// Inicializar use cases
-	templateUseCase := usecase.NewTemplateUseCase(templateRepo)
-	projectUseCase := usecase.NewProjectUseCase(projectRepo, templateRepo, gitService)
+	events := usecase.NewEventBus()
+	templateUseCase := usecase.NewTemplateUseCase(templateRepo, providers, events)
+	logManager := usecase.NewLogManager(logRepo, usecase.LogRetention{
+		MaxEntries: cfg.LogMaxEntries,
+		TTL:        cfg.LogRetentionTTL,
+	})
+	logManager.Start(context.Background())
+	projectUseCase := usecase.NewProjectUseCase(projectRepo, templateRepo, jobRepo, stepRepo, auditRepo, logManager, events, providers)
+
+	// Recuperar jobs interrompidos e iniciar os workers de criação
+	queue := usecase.QueueConfig{
+		Workers:        cfg.JobWorkers,
+		ProviderLimits: cfg.ProviderConcurrency,
+	}
+	if err := projectUseCase.Start(context.Background(), queue); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start project workers")
+	}
 
 	// Inicializar handlers
 	templateHandler := handler.NewTemplateHandler(templateUseCase)
 	projectHandler := handler.NewProjectHandler(projectUseCase)
+	eventHandler := handler.NewEventHandler(events)
 
 	// Criar aplicação Fiber
 	app := fiber.New(fiber.Config{
@@ -82,6 +143,8 @@ func main() {
 	templates.Post("/", templateHandler.CreateTemplate)
 	templates.Get("/", templateHandler.GetAllTemplates)
 	templates.Get("/:id", templateHandler.GetTemplate)
+	templates.Get("/:id/inputs", templateHandler.GetTemplateInputs)
+	templates.Post("/:id/refresh", templateHandler.RefreshTemplate)
 	templates.Put("/:id", templateHandler.UpdateTemplate)
 	templates.Delete("/:id", templateHandler.DeleteTemplate)
 
@@ -89,10 +152,20 @@ func main() {
 	projects := api.Group("/projects")
 	projects.Post("/", projectHandler.CreateProject)
 	projects.Get("/", projectHandler.GetAllProjects)
+	// Registrada antes de /:id para não ser tratada como um ID
+	projects.Get("/ws", websocket.New(projectHandler.StreamEvents))
 	projects.Get("/:id", projectHandler.GetProject)
 	projects.Get("/:id/logs", projectHandler.StreamLogs)
+	projects.Post("/:id/cancel", projectHandler.CancelProject)
+	projects.Post("/:id/retry", projectHandler.RetryProject)
 	projects.Delete("/:id", projectHandler.DeleteProject)
 
+	// Eventos de domínio de todos os recursos
+	api.Get("/events", eventHandler.StreamEvents)
+
+	// Provedores Git disponíveis
+	api.Get("/providers", projectHandler.ListProviders)
+
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
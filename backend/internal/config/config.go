@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -9,8 +13,35 @@ import (
 // Config representa a configuração da aplicação
 type Config struct {
 	Port           string
+	GitProvider    string
 	GitHubToken    string
 	GitHubUsername string
+	GitHubAPIURL   string
+
+	GitLabURL           string
+	GitLabToken         string
+	GitLabNamespace     string
+	GitLabVisibility    string
+	GitLabDefaultBranch string
+
+	GiteaURL           string
+	GiteaToken         string
+	GiteaOwner         string
+	GiteaUsername      string
+	GiteaPrivate       bool
+	GiteaDefaultBranch string
+
+	LocalReposDir string
+
+	// JobWorkers limita quantos projetos são criados ao mesmo tempo
+	JobWorkers int
+	// ProviderConcurrency limita as criações simultâneas por provedor
+	ProviderConcurrency map[string]int
+
+	// LogMaxEntries limita os eventos de log mantidos em memória por projeto
+	LogMaxEntries int
+	// LogRetentionTTL é o tempo que o log de uma criação encerrada fica em memória
+	LogRetentionTTL time.Duration
 }
 
 // LoadConfig carrega a configuração da aplicação
@@ -20,13 +51,75 @@ func LoadConfig() (*Config, error) {
 
 	config := &Config{
 		Port:           getEnv("PORT", "8080"),
+		GitProvider:    getEnv("GIT_PROVIDER", "github"),
 		GitHubToken:    getEnv("GITHUB_TOKEN", ""),
 		GitHubUsername: getEnv("GITHUB_USERNAME", ""),
+		GitHubAPIURL:   getEnv("GITHUB_API_URL", ""),
+
+		GitLabURL:           getEnv("GITLAB_URL", "https://gitlab.com"),
+		GitLabToken:         getEnv("GITLAB_TOKEN", ""),
+		GitLabNamespace:     getEnv("GITLAB_NAMESPACE", ""),
+		GitLabVisibility:    getEnv("GITLAB_VISIBILITY", "private"),
+		GitLabDefaultBranch: getEnv("GITLAB_DEFAULT_BRANCH", "main"),
+
+		GiteaURL:           getEnv("GITEA_URL", ""),
+		GiteaToken:         getEnv("GITEA_TOKEN", ""),
+		GiteaOwner:         getEnv("GITEA_OWNER", ""),
+		GiteaUsername:      getEnv("GITEA_USERNAME", ""),
+		GiteaPrivate:       getEnv("GITEA_PRIVATE", "true") == "true",
+		GiteaDefaultBranch: getEnv("GITEA_DEFAULT_BRANCH", "main"),
+
+		LocalReposDir: getEnv("LOCAL_REPOS_DIR", ""),
+	}
+
+	workers, err := strconv.Atoi(getEnv("JOB_WORKERS", "4"))
+	if err != nil || workers < 1 {
+		return nil, fmt.Errorf("invalid JOB_WORKERS: must be a positive integer")
 	}
+	config.JobWorkers = workers
+
+	limits, err := parseLimits(getEnv("PROVIDER_CONCURRENCY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROVIDER_CONCURRENCY: %w", err)
+	}
+	config.ProviderConcurrency = limits
+
+	maxEntries, err := strconv.Atoi(getEnv("LOG_MAX_ENTRIES", "1000"))
+	if err != nil || maxEntries < 1 {
+		return nil, fmt.Errorf("invalid LOG_MAX_ENTRIES: must be a positive integer")
+	}
+	config.LogMaxEntries = maxEntries
+
+	ttl, err := time.ParseDuration(getEnv("LOG_RETENTION_TTL", "10m"))
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid LOG_RETENTION_TTL: must be a positive duration")
+	}
+	config.LogRetentionTTL = ttl
 
 	return config, nil
 }
 
+// parseLimits lê uma lista no formato "github=2,gitlab=4"
+func parseLimits(value string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected provider=limit, got %q", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("limit for %s must be a positive integer", name)
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits, nil
+}
+
 // getEnv obtém uma variável de ambiente ou retorna um valor padrão
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// AuditEntry registra uma operação com efeito fora da aplicação, como a
+// remoção de um repositório remoto
+type AuditEntry struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Action      string `json:"action" gorm:"not null;index"`
+	ProjectID   uint   `json:"project_id" gorm:"index"`
+	ProjectName string `json:"project_name"`
+	RepoURL     string `json:"repo_url"`
+	// Remote é a ação solicitada para o repositório remoto: delete, archive ou
+	// keep; none quando o projeto não tem repositório remoto
+	Remote string `json:"remote"`
+	// Result é succeeded ou failed; Error traz o motivo da falha
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditAction identifica as operações auditadas
+const (
+	AuditActionProjectDelete   = "project.delete"
+	AuditActionProjectRollback = "project.rollback"
+)
+
+// AuditRemoteNone registra em Remote a remoção de um projeto sem repositório remoto
+const AuditRemoteNone = "none"
+
+// AuditResult representa o resultado de uma operação auditada
+const (
+	AuditResultSucceeded = "succeeded"
+	AuditResultFailed    = "failed"
+)
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+)
+
+// DomainEvent representa uma mudança em um recurso da aplicação publicada no
+// barramento de eventos. ID é crescente enquanto o servidor está no ar.
+type DomainEvent struct {
+	ID         uint64      `json:"id"`
+	Type       string      `json:"type"`
+	ResourceID uint        `json:"resource_id"`
+	Data       interface{} `json:"data,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+// EventType representa os tipos de evento de domínio
+const (
+	EventTemplateCreated      = "template.created"
+	EventTemplateUpdated      = "template.updated"
+	EventTemplateDeleted      = "template.deleted"
+	EventProjectQueued        = "project.queued"
+	EventProjectStatusChanged = "project.status_changed"
+	EventProjectDeleted       = "project.deleted"
+)
+
+// ProjectStatusChange é o conteúdo do evento project.status_changed
+type ProjectStatusChange struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+	FailedStep     string `json:"failed_step,omitempty"`
+}
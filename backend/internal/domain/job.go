@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectJob representa uma execução da criação de um projeto na fila persistida.
+// Provider replica o provedor do projeto para aplicar o limite por provedor.
+type ProjectJob struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ProjectID  uint       `json:"project_id" gorm:"not null;index"`
+	Provider   string     `json:"provider" gorm:"index"`
+	Status     string     `json:"status" gorm:"not null;index;default:'pending'"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// JobStatus representa os possíveis status de um job
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusDone      = "done"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectLog representa um evento do log de criação de um projeto. Sequence é
+// crescente por projeto e define a ordem dos eventos.
+type ProjectLog struct {
+	ID        uint `json:"-" gorm:"primaryKey"`
+	ProjectID uint `json:"project_id" gorm:"not null;uniqueIndex:idx_project_log_sequence"`
+	Sequence  int  `json:"sequence" gorm:"not null;uniqueIndex:idx_project_log_sequence"`
+	// Type classifica o evento: log, status, step ou lagged
+	Type    string `json:"type" gorm:"not null;default:'log'"`
+	Level   string `json:"level" gorm:"not null"`
+	Step    string `json:"step,omitempty"`
+	Message string `json:"message"`
+	// Error traz o detalhe técnico de uma falha
+	Error string `json:"error,omitempty"`
+	// Status é o novo status do projeto em eventos status e o resultado da
+	// etapa em eventos step
+	Status string `json:"status,omitempty"`
+	// DurationMs é a duração da etapa em eventos step de conclusão
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	CreatedAt  time.Time `json:"timestamp"`
+}
+
+// LogEventType representa os tipos de evento do log
+const (
+	LogEventLog    = "log"
+	LogEventStatus = "status"
+	LogEventStep   = "step"
+	// LogEventLagged avisa o assinante de que eventos deixaram de estar
+	// disponíveis; não é gravado
+	LogEventLagged = "lagged"
+)
+
+// LogLevel representa os níveis das mensagens de log
+const (
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
@@ -0,0 +1,39 @@
+package domain
+
+import "regexp"
+
+// TemplateManifest descreve as entradas declaradas no arquivo de manifesto do template
+type TemplateManifest struct {
+	Inputs []TemplateInput `json:"inputs" yaml:"inputs"`
+	Rules  []TemplateRule  `json:"rules,omitempty" yaml:"rules"`
+}
+
+// TemplateInput representa uma variável que o template espera receber
+type TemplateInput struct {
+	Name        string   `json:"name" yaml:"name"`
+	Type        string   `json:"type" yaml:"type"`
+	Default     any      `json:"default,omitempty" yaml:"default"`
+	Description string   `json:"description,omitempty" yaml:"description"`
+	Regex       string   `json:"regex,omitempty" yaml:"regex"`
+	Required    bool     `json:"required" yaml:"required"`
+	Choices     []string `json:"choices,omitempty" yaml:"choices"`
+	// Pattern é Regex já compilada, preenchida ao interpretar o manifesto
+	Pattern *regexp.Regexp `json:"-" yaml:"-"`
+}
+
+// TemplateRule condiciona a presença de arquivos no projeto gerado às respostas.
+// Arquivos que casam com Include só são mantidos quando When é verdadeira;
+// arquivos que casam com Exclude são removidos quando When é verdadeira.
+type TemplateRule struct {
+	Include []string `json:"include,omitempty" yaml:"include"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude"`
+	When    string   `json:"when" yaml:"when"`
+}
+
+// Tipos de entrada suportados pelo manifesto
+const (
+	InputTypeString = "string"
+	InputTypeBool   = "bool"
+	InputTypeInt    = "int"
+	InputTypeChoice = "choice"
+)
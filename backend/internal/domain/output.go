@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+)
+
+// OutputFunc recebe cada linha da saída dos comandos executados por um
+// GitService, já com as credenciais removidas
+type OutputFunc func(line string)
+
+type outputKey struct{}
+
+// WithOutput retorna um contexto em que os comandos git enviam a saída, linha
+// a linha, para fn. Um fn nil descarta a saída.
+func WithOutput(ctx context.Context, fn OutputFunc) context.Context {
+	return context.WithValue(ctx, outputKey{}, fn)
+}
+
+// OutputFrom retorna a função registrada com WithOutput, ou nil
+func OutputFrom(ctx context.Context) OutputFunc {
+	fn, _ := ctx.Value(outputKey{}).(OutputFunc)
+	return fn
+}
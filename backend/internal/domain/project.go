@@ -9,23 +9,99 @@ type Project struct {
 	ID   uint   `json:"id" gorm:"primaryKey"`
 	Name string `json:"name" gorm:"not null"`
 	// GitURL pode ser preenchido após a criação do repositório no GitHub
-	GitURL     string    `json:"git_url"`
-	TemplateID uint      `json:"template_id" gorm:"not null"`
-	Template   Template  `json:"template" gorm:"foreignKey:TemplateID"`
-	Status     string    `json:"status" gorm:"default:'creating'"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	GitURL     string   `json:"git_url"`
+	TemplateID uint     `json:"template_id" gorm:"not null"`
+	Template   Template `json:"template" gorm:"foreignKey:TemplateID"`
+	Status     string   `json:"status" gorm:"default:'queued'"`
+	// Provider é o provedor de hospedagem onde o repositório é criado
+	Provider string `json:"provider"`
+	// TemplateRef é a ref solicitada e TemplateCommit o SHA em que ela foi resolvida
+	TemplateRef    string `json:"template_ref"`
+	TemplateCommit string `json:"template_commit"`
+	// FailedStep é a etapa em que a última criação falhou; a nova tentativa
+	// retoma a partir dela
+	FailedStep string `json:"failed_step,omitempty"`
+	// Variables guarda os valores usados para renderizar o template
+	Variables map[string]any `json:"variables" gorm:"serializer:json"`
+	// Steps registra as etapas da tentativa de criação mais recente
+	Steps []ProjectStep `json:"steps,omitempty" gorm:"foreignKey:ProjectID"`
+	// Job é o job de criação mais recente, preenchido ao buscar o projeto
+	Job *ProjectJob `json:"job,omitempty" gorm:"-"`
+	// QueuePosition é a posição na fila de criação (1 é o próximo), zero fora da fila
+	QueuePosition int       `json:"queue_position,omitempty" gorm:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // CreateProjectRequest representa a requisição para criar um projeto
 type CreateProjectRequest struct {
 	Name       string `json:"name" validate:"required"`
 	TemplateID uint   `json:"template_id" validate:"required"`
+	// TemplateRef sobrescreve a ref configurada no template
+	TemplateRef string `json:"template_ref"`
+	// Provider seleciona o provedor de hospedagem; vazio usa o padrão
+	Provider string `json:"provider"`
+	// Variables contém os valores para os placeholders do template
+	Variables map[string]any `json:"variables"`
 }
 
 // ProjectStatus representa os possíveis status de um projeto
 const (
-	ProjectStatusCreating = "creating"
-	ProjectStatusReady    = "ready"
-	ProjectStatusError    = "error"
+	ProjectStatusQueued         = "queued"
+	ProjectStatusCloning        = "cloning"
+	ProjectStatusRendering      = "rendering"
+	ProjectStatusCreatingRemote = "creating_remote"
+	ProjectStatusPushing        = "pushing"
+	ProjectStatusReady          = "ready"
+	ProjectStatusError          = "error"
+	ProjectStatusCancelled      = "cancelled"
 )
+
+// projectTransitions define a máquina de estados da criação de um projeto.
+// Os status em andamento podem voltar para "queued" quando um job
+// interrompido é recolocado na fila; "rendering" segue direto para "pushing"
+// quando o repositório remoto de uma tentativa anterior é reaproveitado.
+var projectTransitions = map[string][]string{
+	ProjectStatusQueued:         {ProjectStatusCloning, ProjectStatusError, ProjectStatusCancelled},
+	ProjectStatusCloning:        {ProjectStatusRendering, ProjectStatusQueued, ProjectStatusError, ProjectStatusCancelled},
+	ProjectStatusRendering:      {ProjectStatusCreatingRemote, ProjectStatusPushing, ProjectStatusQueued, ProjectStatusError, ProjectStatusCancelled},
+	ProjectStatusCreatingRemote: {ProjectStatusPushing, ProjectStatusQueued, ProjectStatusError, ProjectStatusCancelled},
+	ProjectStatusPushing:        {ProjectStatusReady, ProjectStatusQueued, ProjectStatusError, ProjectStatusCancelled},
+	ProjectStatusError:          {ProjectStatusQueued},
+	ProjectStatusCancelled:      {ProjectStatusQueued},
+}
+
+// CanTransition informa se o projeto pode passar do status from para to.
+// Permanecer no mesmo status é sempre permitido.
+func CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range projectTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InProgressStatuses retorna os status de um projeto cuja criação não terminou
+func InProgressStatuses() []string {
+	return []string{
+		ProjectStatusQueued,
+		ProjectStatusCloning,
+		ProjectStatusRendering,
+		ProjectStatusCreatingRemote,
+		ProjectStatusPushing,
+	}
+}
+
+// IsInProgress informa se a criação do projeto ainda não terminou
+func IsInProgress(status string) bool {
+	for _, s := range InProgressStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -20,12 +20,73 @@ type ProjectRepository interface {
 	Update(ctx context.Context, project *Project) error
 	Delete(ctx context.Context, id uint) error
 	GetByName(ctx context.Context, name string) (*Project, error)
+	GetByStatus(ctx context.Context, statuses ...string) ([]*Project, error)
+}
+
+// ProjectStepRepository define as operações de persistência das etapas de criação
+type ProjectStepRepository interface {
+	// Replace substitui as etapas registradas para o projeto
+	Replace(ctx context.Context, projectID uint, steps []ProjectStep) error
+	Update(ctx context.Context, step *ProjectStep) error
+	DeleteByProjectID(ctx context.Context, projectID uint) error
+}
+
+// LogRepository define as operações de persistência dos logs de criação
+type LogRepository interface {
+	Create(ctx context.Context, entry *ProjectLog) error
+	// GetByProjectID retorna os logs do projeto com sequência maior que after,
+	// em ordem de sequência
+	GetByProjectID(ctx context.Context, projectID uint, after int) ([]*ProjectLog, error)
+	// GetRecentByProjectID retorna os limit logs mais recentes do projeto, em
+	// ordem de sequência
+	GetRecentByProjectID(ctx context.Context, projectID uint, limit int) ([]*ProjectLog, error)
+	DeleteByProjectID(ctx context.Context, projectID uint) error
+}
+
+// AuditRepository define as operações de persistência do registro de auditoria
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditEntry) error
+}
+
+// JobRepository define as operações de persistência da fila de jobs
+type JobRepository interface {
+	Create(ctx context.Context, job *ProjectJob) error
+	Update(ctx context.Context, job *ProjectJob) error
+	// ClaimNext marca o job pendente mais antigo como em execução e o retorna,
+	// ignorando os provedores informados; retorna nil quando não há job elegível
+	ClaimNext(ctx context.Context, excludeProviders []string) (*ProjectJob, error)
+	GetByStatus(ctx context.Context, status string) ([]*ProjectJob, error)
+	GetLatestByProjectID(ctx context.Context, projectID uint) (*ProjectJob, error)
+	DeleteByProjectID(ctx context.Context, projectID uint) error
+}
+
+// CloneSource identifica o conteúdo a ser clonado de um repositório de template
+type CloneSource struct {
+	GitURL string
+	// Ref é um branch, tag ou SHA; vazio usa o branch padrão
+	Ref string
+	// Subdirectory limita o checkout a uma pasta do repositório
+	Subdirectory string
 }
 
 // GitService define as operações com repositórios Git
 type GitService interface {
-	CloneRepository(ctx context.Context, gitURL, destPath string) error
+	// CloneRepository clona a origem em destPath e retorna o SHA do commit resolvido
+	CloneRepository(ctx context.Context, source CloneSource, destPath string) (string, error)
 	CreateRepository(ctx context.Context, name, description string) (string, error)
 	PushToRepository(ctx context.Context, localPath, repoURL string) error
 	ClearGitHistory(ctx context.Context, repoPath string) error
+	// DeleteRepository remove o repositório remoto identificado pela URL
+	// retornada em CreateRepository
+	DeleteRepository(ctx context.Context, repoURL string) error
+	// ArchiveRepository arquiva o repositório remoto, mantendo-o somente leitura
+	ArchiveRepository(ctx context.Context, repoURL string) error
+}
+
+// GitProviders resolve o GitService de cada provedor de hospedagem configurado
+type GitProviders interface {
+	// Get retorna o serviço do provedor; nome vazio resolve para o provedor padrão
+	Get(name string) (GitService, error)
+	Default() string
+	Names() []string
 }
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectStep registra a execução de uma etapa da criação de um projeto
+type ProjectStep struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ProjectID  uint       `json:"project_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	Status     string     `json:"status" gorm:"not null;default:'pending'"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// Etapas da criação de um projeto, na ordem em que são executadas
+const (
+	ProjectStepClone            = "clone"
+	ProjectStepClearHistory     = "clear_history"
+	ProjectStepRender           = "render"
+	ProjectStepCreateRepository = "create_repository"
+	ProjectStepPush             = "push"
+)
+
+// StepStatus representa os possíveis status de uma etapa
+const (
+	StepStatusPending   = "pending"
+	StepStatusRunning   = "running"
+	StepStatusDone      = "done"
+	StepStatusFailed    = "failed"
+	StepStatusSkipped   = "skipped"
+	StepStatusCancelled = "cancelled"
+)
+
+// ProjectSteps retorna as etapas da criação na ordem de execução
+func ProjectSteps() []string {
+	return []string{
+		ProjectStepClone,
+		ProjectStepClearHistory,
+		ProjectStepRender,
+		ProjectStepCreateRepository,
+		ProjectStepPush,
+	}
+}
+
+// StepStatus retorna o status do projeto enquanto a etapa executa
+func StepStatus(step string) string {
+	switch step {
+	case ProjectStepClone, ProjectStepClearHistory:
+		return ProjectStatusCloning
+	case ProjectStepRender:
+		return ProjectStatusRendering
+	case ProjectStepCreateRepository:
+		return ProjectStatusCreatingRemote
+	default:
+		return ProjectStatusPushing
+	}
+}
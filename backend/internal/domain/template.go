@@ -6,30 +6,65 @@ import (
 
 // Template representa um template de repositório
 type Template struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name" gorm:"not null;unique"`
-	Description string    `json:"description"`
-	GitURL      string    `json:"git_url" gorm:"not null"`
-	Language    string    `json:"language"`
-	Tags        string    `json:"tags"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"not null;unique"`
+	Description string `json:"description"`
+	GitURL      string `json:"git_url" gorm:"not null"`
+	// Ref fixa o template em um branch, tag ou SHA; vazio usa o branch padrão
+	Ref string `json:"ref"`
+	// Subdirectory aponta a pasta do template quando ele vive em um monorepo
+	Subdirectory string `json:"subdirectory"`
+	Language     string `json:"language"`
+	Tags         string `json:"tags"`
+	// RollbackPolicy define o que fazer com o repositório remoto quando a
+	// criação falha depois de ele ter sido criado
+	RollbackPolicy string `json:"rollback_policy" gorm:"default:'delete'"`
+	// Manifest é preenchido a partir do template.yaml do repositório, se existir
+	Manifest  *TemplateManifest `json:"manifest,omitempty" gorm:"serializer:json"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
 }
 
 // CreateTemplateRequest representa a requisição para criar um template
 type CreateTemplateRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description"`
-	GitURL      string `json:"git_url" validate:"required,url"`
-	Language    string `json:"language"`
-	Tags        string `json:"tags"`
+	Name         string `json:"name" validate:"required"`
+	Description  string `json:"description"`
+	GitURL       string `json:"git_url" validate:"required,url"`
+	Ref          string `json:"ref"`
+	Subdirectory string `json:"subdirectory"`
+	Language     string `json:"language"`
+	Tags         string `json:"tags"`
+	// RollbackPolicy aceita delete, archive ou keep; vazio usa delete
+	RollbackPolicy string `json:"rollback_policy"`
 }
 
-// UpdateTemplateRequest representa a requisição para atualizar um template
+// UpdateTemplateRequest representa a requisição para atualizar um template.
+// Ref e Subdirectory ausentes mantêm o valor atual; vazios voltam ao branch
+// padrão e à raiz do repositório.
 type UpdateTemplateRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	GitURL      string `json:"git_url" validate:"omitempty,url"`
-	Language    string `json:"language"`
-	Tags        string `json:"tags"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	GitURL       string  `json:"git_url" validate:"omitempty,url"`
+	Ref          *string `json:"ref"`
+	Subdirectory *string `json:"subdirectory"`
+	Language     string  `json:"language"`
+	Tags         string  `json:"tags"`
+	// RollbackPolicy aceita delete, archive ou keep
+	RollbackPolicy string `json:"rollback_policy"`
+}
+
+// RollbackPolicy define o destino do repositório remoto de uma criação que falhou
+const (
+	RollbackPolicyDelete  = "delete"
+	RollbackPolicyArchive = "archive"
+	RollbackPolicyKeep    = "keep"
+)
+
+// IsValidRollbackPolicy informa se a política é suportada
+func IsValidRollbackPolicy(policy string) bool {
+	switch policy {
+	case RollbackPolicyDelete, RollbackPolicyArchive, RollbackPolicyKeep:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"template-manager-backend/internal/usecase"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EventHandler gerencia o stream de eventos de domínio
+type EventHandler struct {
+	events *usecase.EventBus
+}
+
+// NewEventHandler cria uma nova instância do handler de eventos
+func NewEventHandler(events *usecase.EventBus) *EventHandler {
+	return &EventHandler{
+		events: events,
+	}
+}
+
+// StreamEvents envia via SSE os eventos de domínio de templates e projetos,
+// usando o tipo do evento como nome. Um cliente que fica para trás tem a
+// conexão encerrada e deve recarregar os recursos ao reconectar.
+func (h *EventHandler) StreamEvents(c *fiber.Ctx) error {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ch, unsubscribe := h.events.Subscribe()
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sseFrame é um evento lido do stream SSE
+type sseFrame struct {
+	id    string
+	event string
+	data  string
+}
+
+// readFrame lê o próximo evento do stream, ignorando comentários
+func readFrame(t *testing.T, r *bufio.Reader) sseFrame {
+	t.Helper()
+	var frame sseFrame
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if frame != (sseFrame{}) {
+				return frame
+			}
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			frame.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			frame.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			frame.data = strings.TrimPrefix(line, "data: ")
+		default:
+			t.Fatalf("unexpected line %q in the event stream", line)
+		}
+	}
+}
+
+func TestStreamEventsFraming(t *testing.T) {
+	events := usecase.NewEventBus()
+	// A inscrição acontece quando o stream começa a ser escrito, então os
+	// eventos são publicados continuamente. A publicação só para depois do
+	// Shutdown, que espera o stream notar a conexão fechada ao escrever.
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			events.Publish(domain.EventProjectStatusChanged, 7, domain.ProjectStatusChange{
+				Name:           "demo",
+				Status:         domain.ProjectStatusReady,
+				PreviousStatus: domain.ProjectStatusPushing,
+			})
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/api/v1/events", NewEventHandler(events).StreamEvents)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/api/v1/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", cc)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	frame := readFrame(t, r)
+	if frame.event != domain.EventProjectStatusChanged {
+		t.Fatalf("event = %q, want %s", frame.event, domain.EventProjectStatusChanged)
+	}
+	var event struct {
+		ID         uint64                     `json:"id"`
+		Type       string                     `json:"type"`
+		ResourceID uint                       `json:"resource_id"`
+		Data       domain.ProjectStatusChange `json:"data"`
+		Timestamp  time.Time                  `json:"timestamp"`
+	}
+	if err := json.Unmarshal([]byte(frame.data), &event); err != nil {
+		t.Fatalf("data %q is not JSON: %v", frame.data, err)
+	}
+	if frame.id != strconv.FormatUint(event.ID, 10) {
+		t.Errorf("id = %q, want the event ID %d", frame.id, event.ID)
+	}
+	if event.Type != frame.event || event.ResourceID != 7 || event.Data.Status != domain.ProjectStatusReady || event.Timestamp.IsZero() {
+		t.Errorf("event = %+v, want the published status change", event)
+	}
+
+	// Os eventos seguintes chegam em ordem de ID
+	next := readFrame(t, r)
+	if id, _ := strconv.ParseUint(next.id, 10, 64); id <= event.ID {
+		t.Errorf("next id = %q, want greater than %d", next.id, event.ID)
+	}
+}
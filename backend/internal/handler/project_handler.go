@@ -2,14 +2,20 @@ package handler
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"template-manager-backend/internal/domain"
 	"template-manager-backend/internal/usecase"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// heartbeatInterval é o intervalo dos sinais enviados nos streams de eventos
+// para que proxies não encerrem conexões ociosas
+const heartbeatInterval = 15 * time.Second
+
 // ProjectHandler gerencia as requisições HTTP para projetos
 type ProjectHandler struct {
 	projectUseCase *usecase.ProjectUseCase
@@ -73,7 +79,8 @@ func (h *ProjectHandler) GetAllProjects(c *fiber.Ctx) error {
 	return c.JSON(projects)
 }
 
-// DeleteProject remove um projeto
+// DeleteProject remove um projeto. Os parâmetros remote e confirm controlam o
+// destino do repositório remoto.
 func (h *ProjectHandler) DeleteProject(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
@@ -83,7 +90,7 @@ func (h *ProjectHandler) DeleteProject(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.projectUseCase.DeleteProject(c.Context(), uint(id)); err != nil {
+	if err := h.projectUseCase.DeleteProject(c.Context(), uint(id), c.Query("remote"), c.Query("confirm")); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -92,7 +99,61 @@ func (h *ProjectHandler) DeleteProject(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
 
-// StreamLogs envia os logs de criação do projeto via SSE.
+// CancelProject interrompe a criação de um projeto
+func (h *ProjectHandler) CancelProject(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	if err := h.projectUseCase.CancelProject(c.Context(), uint(id)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Cancellation requested",
+	})
+}
+
+// RetryProject reenfileira a criação de um projeto com erro
+func (h *ProjectHandler) RetryProject(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid project ID",
+		})
+	}
+
+	project, err := h.projectUseCase.RetryProject(c.Context(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(project)
+}
+
+// ListProviders lista os provedores de hospedagem disponíveis
+func (h *ProjectHandler) ListProviders(c *fiber.Ctx) error {
+	names, defaultName := h.projectUseCase.ListProviders()
+	return c.JSON(fiber.Map{
+		"providers": names,
+		"default":   defaultName,
+	})
+}
+
+// StreamLogs envia os eventos de log da criação do projeto via SSE. Cada evento
+// usa o tipo (log, status ou step) como nome, a sequência como id e o JSON do
+// evento como dados. Uma reconexão com o cabeçalho Last-Event-ID (ou o
+// parâmetro last_event_id) continua a partir do evento seguinte. Quando a
+// criação termina, o evento done informa o status final do projeto.
 func (h *ProjectHandler) StreamLogs(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
@@ -100,14 +161,70 @@ func (h *ProjectHandler) StreamLogs(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid project ID"})
 	}
 
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	after := 0
+	if lastEventID != "" {
+		after, err = strconv.Atoi(lastEventID)
+		if err != nil || after < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Last-Event-ID"})
+		}
+	}
+
+	if _, err := h.projectUseCase.GetProject(c.Context(), uint(id)); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
-		ch := h.projectUseCase.SubscribeLogs(uint(id))
-		for msg := range ch {
-			fmt.Fprintf(w, "data: %s\n\n", msg)
-			w.Flush()
+		sub := h.projectUseCase.SubscribeLogs(uint(id), after)
+		// Parar de entregar eventos quando o cliente desconecta
+		defer sub.Close()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			event, ok, err := sub.Next()
+			if err != nil {
+				h.writeDone(w, uint(id))
+				return
+			}
+			if ok {
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+			} else {
+				select {
+				case <-sub.Ready():
+					continue
+				case <-heartbeat.C:
+					fmt.Fprint(w, ": heartbeat\n\n")
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
 		}
 	})
 	return nil
 }
+
+// writeDone envia o evento done com o status final do projeto. Um stream
+// encerrado com o projeto ainda em andamento termina sem done, para que o
+// cliente reconecte.
+func (h *ProjectHandler) writeDone(w *bufio.Writer, id uint) {
+	status, finished := h.finalStatus(id)
+	if !finished {
+		return
+	}
+	data, err := json.Marshal(fiber.Map{"status": status})
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: done\ndata: %s\n\n", data)
+	w.Flush()
+}
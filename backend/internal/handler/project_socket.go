@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/internal/usecase"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/phuslu/log"
+)
+
+// Ações aceitas no WebSocket de eventos de projetos
+const (
+	socketActionSubscribe   = "subscribe"
+	socketActionUnsubscribe = "unsubscribe"
+)
+
+// socketRequest é uma mensagem enviada pelo cliente no WebSocket de eventos.
+// LastSequence retoma a inscrição após o evento com essa sequência.
+type socketRequest struct {
+	Action       string `json:"action"`
+	ProjectID    uint   `json:"project_id"`
+	LastSequence int    `json:"last_sequence"`
+}
+
+// projectSocket multiplexa num WebSocket os eventos de log de vários projetos.
+// A conexão não aceita escritas concorrentes, por isso todas passam por send.
+type projectSocket struct {
+	h    *ProjectHandler
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	subs map[uint]chan struct{}
+	wg   sync.WaitGroup
+}
+
+// StreamEvents atende o WebSocket de eventos de projetos. O cliente envia
+// {"action":"subscribe","project_id":1} para receber os eventos de log,
+// status e etapas do projeto e {"action":"unsubscribe","project_id":1} para
+// pará-los. Os eventos têm o mesmo formato do SSE de logs; ao fim da criação
+// chega {"type":"done","project_id":1,"status":"ready"}.
+func (h *ProjectHandler) StreamEvents(conn *websocket.Conn) {
+	socket := &projectSocket{
+		h:    h,
+		conn: conn,
+		subs: make(map[uint]chan struct{}),
+	}
+	stopPing := make(chan struct{})
+	go socket.ping(stopPing)
+
+	for {
+		var req socketRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Warn().Err(err).Msg("project events socket closed")
+			}
+			break
+		}
+
+		switch req.Action {
+		case socketActionSubscribe:
+			socket.subscribe(req.ProjectID, req.LastSequence)
+		case socketActionUnsubscribe:
+			socket.unsubscribe(req.ProjectID)
+		default:
+			socket.send(fiber.Map{"type": "error", "error": "Invalid action"})
+		}
+	}
+
+	// A conexão é liberada quando o handler retorna; as goroutines que
+	// escrevem nela precisam terminar antes
+	close(stopPing)
+	socket.mu.Lock()
+	for id, stop := range socket.subs {
+		close(stop)
+		delete(socket.subs, id)
+	}
+	socket.mu.Unlock()
+	socket.wg.Wait()
+}
+
+// subscribe começa a encaminhar os eventos do projeto; uma inscrição repetida
+// é ignorada
+func (s *projectSocket) subscribe(id uint, after int) {
+	if _, err := s.h.projectUseCase.GetProject(context.Background(), id); err != nil {
+		s.send(fiber.Map{"type": "error", "project_id": id, "error": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[id]; ok {
+		return
+	}
+	stop := make(chan struct{})
+	s.subs[id] = stop
+	s.wg.Add(1)
+	go s.forward(id, s.h.projectUseCase.SubscribeLogs(id, after), stop)
+}
+
+// unsubscribe para de encaminhar os eventos do projeto
+func (s *projectSocket) unsubscribe(id uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stop, ok := s.subs[id]; ok {
+		close(stop)
+		delete(s.subs, id)
+	}
+}
+
+// forward envia os eventos da inscrição até o log encerrar ou stop ser fechado
+func (s *projectSocket) forward(id uint, sub *usecase.LogSubscription, stop chan struct{}) {
+	defer s.wg.Done()
+	defer sub.Close()
+
+	for {
+		event, ok, err := sub.Next()
+		// Nada do que for lido depois de unsubscribe é enviado, mesmo que
+		// Ready e stop tenham sido sinalizados juntos
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err != nil {
+			if status, finished := s.h.finalStatus(id); finished {
+				s.send(fiber.Map{"type": "done", "project_id": id, "status": status})
+			}
+			s.mu.Lock()
+			if s.subs[id] == stop {
+				delete(s.subs, id)
+			}
+			s.mu.Unlock()
+			return
+		}
+		if ok {
+			if s.send(event) != nil {
+				return
+			}
+			continue
+		}
+		select {
+		case <-sub.Ready():
+		case <-stop:
+			return
+		}
+	}
+}
+
+// ping mantém a conexão ativa através de proxies até stop ser fechado
+func (s *projectSocket) ping(stop chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeatInterval))
+			s.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send escreve uma mensagem JSON na conexão
+func (s *projectSocket) send(message interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(message)
+}
+
+// finalStatus retorna o status do projeto e se a criação já terminou
+func (h *ProjectHandler) finalStatus(id uint) (string, bool) {
+	project, err := h.projectUseCase.GetProject(context.Background(), id)
+	if err != nil || domain.IsInProgress(project.Status) {
+		return "", false
+	}
+	return project.Status, true
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/internal/repository"
+	"template-manager-backend/internal/usecase"
+	"template-manager-backend/pkg/database"
+	"template-manager-backend/pkg/gitprovider"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// socketMessage reúne os campos das mensagens do WebSocket usados nos testes
+type socketMessage struct {
+	Type      string `json:"type"`
+	ProjectID uint   `json:"project_id"`
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+}
+
+// socketFixture sobe o WebSocket de eventos de projetos num listener local
+type socketFixture struct {
+	projects domain.ProjectRepository
+	logs     *usecase.LogManager
+	url      string
+}
+
+func newSocketFixture(t *testing.T) *socketFixture {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectRepo := repository.NewProjectRepository(db)
+	logs := usecase.NewLogManager(repository.NewLogRepository(db), usecase.LogRetention{})
+	projectUseCase := usecase.NewProjectUseCase(
+		projectRepo,
+		repository.NewTemplateRepository(db),
+		repository.NewJobRepository(db),
+		repository.NewProjectStepRepository(db),
+		repository.NewAuditRepository(db),
+		logs,
+		usecase.NewEventBus(),
+		gitprovider.NewRegistry("local"),
+	)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/api/v1/projects/ws", websocket.New(NewProjectHandler(projectUseCase).StreamEvents))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	return &socketFixture{
+		projects: projectRepo,
+		logs:     logs,
+		url:      "ws://" + ln.Addr().String() + "/api/v1/projects/ws",
+	}
+}
+
+// createProject grava um projeto em criação
+func (f *socketFixture) createProject(t *testing.T, name string) *domain.Project {
+	t.Helper()
+	project := &domain.Project{
+		Name:     name,
+		Template: domain.Template{Name: "template-" + name, GitURL: "https://example.com/" + name + ".git"},
+		Status:   domain.ProjectStatusCloning,
+		Provider: "local",
+	}
+	if err := f.projects.Create(context.Background(), project); err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func (f *socketFixture) dial(t *testing.T) *fastws.Conn {
+	t.Helper()
+	conn, _, err := fastws.DefaultDialer.Dial(f.url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func send(t *testing.T, conn *fastws.Conn, request socketRequest) {
+	t.Helper()
+	if err := conn.WriteJSON(request); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive(t *testing.T, conn *fastws.Conn) socketMessage {
+	t.Helper()
+	var message socketMessage
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+// barrier aguarda o servidor processar as mensagens enviadas antes dela: a
+// ação inválida só é respondida depois das anteriores
+func barrier(t *testing.T, conn *fastws.Conn) {
+	t.Helper()
+	send(t, conn, socketRequest{Action: "sync"})
+	if message := receive(t, conn); message.Type != "error" || message.Error != "Invalid action" {
+		t.Fatalf("expected the invalid action reply, got %+v", message)
+	}
+}
+
+func TestStreamEventsMultiplexesProjects(t *testing.T) {
+	f := newSocketFixture(t)
+	first := f.createProject(t, "first")
+	second := f.createProject(t, "second")
+	f.logs.Append(first.ID, "first started")
+	f.logs.Append(second.ID, "second started")
+
+	conn := f.dial(t)
+	send(t, conn, socketRequest{Action: socketActionSubscribe, ProjectID: first.ID})
+	send(t, conn, socketRequest{Action: socketActionSubscribe, ProjectID: second.ID})
+
+	got := map[uint]string{}
+	for i := 0; i < 2; i++ {
+		message := receive(t, conn)
+		got[message.ProjectID] = message.Message
+	}
+	if got[first.ID] != "first started" || got[second.ID] != "second started" {
+		t.Fatalf("events by project = %v", got)
+	}
+
+	f.logs.Append(second.ID, "second cloning")
+	f.logs.Append(first.ID, "first cloning")
+	got = map[uint]string{}
+	for i := 0; i < 2; i++ {
+		message := receive(t, conn)
+		got[message.ProjectID] = message.Message
+	}
+	if got[first.ID] != "first cloning" || got[second.ID] != "second cloning" {
+		t.Fatalf("events by project = %v", got)
+	}
+}
+
+func TestStreamEventsUnsubscribeStopsDelivery(t *testing.T) {
+	f := newSocketFixture(t)
+	first := f.createProject(t, "first")
+	second := f.createProject(t, "second")
+	f.logs.Append(first.ID, "first started")
+	f.logs.Append(second.ID, "second started")
+
+	conn := f.dial(t)
+	send(t, conn, socketRequest{Action: socketActionSubscribe, ProjectID: first.ID})
+	send(t, conn, socketRequest{Action: socketActionSubscribe, ProjectID: second.ID})
+	receive(t, conn)
+	receive(t, conn)
+
+	send(t, conn, socketRequest{Action: socketActionUnsubscribe, ProjectID: second.ID})
+	barrier(t, conn)
+
+	f.logs.Append(second.ID, "second cloning")
+	f.logs.Append(first.ID, "first cloning")
+	if message := receive(t, conn); message.ProjectID != first.ID || message.Message != "first cloning" {
+		t.Fatalf("expected only the subscribed project's event, got %+v", message)
+	}
+	// Nenhum evento do projeto desinscrito pode ter chegado antes da resposta
+	barrier(t, conn)
+}
+
+func TestStreamEventsSendsDoneWhenLogCloses(t *testing.T) {
+	f := newSocketFixture(t)
+	project := f.createProject(t, "project")
+	f.logs.Append(project.ID, "started")
+
+	conn := f.dial(t)
+	send(t, conn, socketRequest{Action: socketActionSubscribe, ProjectID: project.ID, LastSequence: 1})
+	barrier(t, conn)
+
+	project.Status = domain.ProjectStatusReady
+	if err := f.projects.Update(context.Background(), project); err != nil {
+		t.Fatal(err)
+	}
+	f.logs.Append(project.ID, "Project ready")
+	f.logs.Close(project.ID)
+
+	if message := receive(t, conn); message.Type != domain.LogEventLog || message.Message != "Project ready" {
+		t.Fatalf("expected the last log event, got %+v", message)
+	}
+	message := receive(t, conn)
+	if message.Type != "done" || message.ProjectID != project.ID || message.Status != domain.ProjectStatusReady {
+		t.Fatalf("expected done with the final status, got %+v", message)
+	}
+}
@@ -98,6 +98,46 @@ func (h *TemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
 	return c.JSON(template)
 }
 
+// GetTemplateInputs retorna as entradas declaradas no manifesto do template
+func (h *TemplateHandler) GetTemplateInputs(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid template ID",
+		})
+	}
+
+	inputs, err := h.templateUseCase.GetTemplateInputs(c.Context(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(inputs)
+}
+
+// RefreshTemplate relê o manifesto do repositório do template
+func (h *TemplateHandler) RefreshTemplate(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid template ID",
+		})
+	}
+
+	template, err := h.templateUseCase.RefreshTemplate(c.Context(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(template)
+}
+
 // DeleteTemplate remove um template
 func (h *TemplateHandler) DeleteTemplate(c *fiber.Ctx) error {
 	idParam := c.Params("id")
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"template-manager-backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// auditRepository implementa domain.AuditRepository
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository cria uma nova instância do repositório de auditoria
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create grava uma entrada de auditoria
+func (r *auditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
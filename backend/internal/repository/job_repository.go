@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"template-manager-backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// jobRepository implementa domain.JobRepository
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository cria uma nova instância do repositório de jobs
+func NewJobRepository(db *gorm.DB) domain.JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Create cria um novo job
+func (r *jobRepository) Create(ctx context.Context, job *domain.ProjectJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// Update atualiza um job existente
+func (r *jobRepository) Update(ctx context.Context, job *domain.ProjectJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// ClaimNext reivindica o job pendente mais antigo cujo provedor não esteja em
+// excludeProviders. A atualização condicionada ao status garante que dois
+// workers não executem o mesmo job.
+func (r *jobRepository) ClaimNext(ctx context.Context, excludeProviders []string) (*domain.ProjectJob, error) {
+	for {
+		// Find com Limit evita o log de "record not found" a cada consulta vazia
+		var jobs []domain.ProjectJob
+		query := r.db.WithContext(ctx).Where("status = ?", domain.JobStatusPending)
+		if len(excludeProviders) > 0 {
+			query = query.Where("provider NOT IN ?", excludeProviders)
+		}
+		err := query.
+			Order("id").
+			Limit(1).
+			Find(&jobs).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(jobs) == 0 {
+			return nil, nil
+		}
+		job := jobs[0]
+
+		now := time.Now()
+		result := r.db.WithContext(ctx).Model(&domain.ProjectJob{}).
+			Where("id = ? AND status = ?", job.ID, domain.JobStatusPending).
+			Updates(map[string]interface{}{
+				"status":      domain.JobStatusRunning,
+				"attempts":    gorm.Expr("attempts + 1"),
+				"started_at":  now,
+				"finished_at": nil,
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Outro worker reivindicou o job primeiro
+			continue
+		}
+
+		job.Status = domain.JobStatusRunning
+		job.Attempts++
+		job.StartedAt = &now
+		job.FinishedAt = nil
+		return &job, nil
+	}
+}
+
+// GetByStatus busca os jobs com o status informado
+func (r *jobRepository) GetByStatus(ctx context.Context, status string) ([]*domain.ProjectJob, error) {
+	var jobs []*domain.ProjectJob
+	err := r.db.WithContext(ctx).Where("status = ?", status).Order("id").Find(&jobs).Error
+	return jobs, err
+}
+
+// GetLatestByProjectID busca o job mais recente de um projeto
+func (r *jobRepository) GetLatestByProjectID(ctx context.Context, projectID uint) (*domain.ProjectJob, error) {
+	var job domain.ProjectJob
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id desc").First(&job).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// DeleteByProjectID remove os jobs de um projeto
+func (r *jobRepository) DeleteByProjectID(ctx context.Context, projectID uint) error {
+	return r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&domain.ProjectJob{}).Error
+}
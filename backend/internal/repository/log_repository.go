@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"template-manager-backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// logRepository implementa domain.LogRepository
+type logRepository struct {
+	db *gorm.DB
+}
+
+// NewLogRepository cria uma nova instância do repositório de logs
+func NewLogRepository(db *gorm.DB) domain.LogRepository {
+	return &logRepository{db: db}
+}
+
+// Create grava uma linha de log
+func (r *logRepository) Create(ctx context.Context, entry *domain.ProjectLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// GetByProjectID busca os logs de um projeto posteriores a after em ordem de sequência
+func (r *logRepository) GetByProjectID(ctx context.Context, projectID uint, after int) ([]*domain.ProjectLog, error) {
+	var entries []*domain.ProjectLog
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND sequence > ?", projectID, after).
+		Order("sequence").
+		Find(&entries).Error
+	return entries, err
+}
+
+// GetRecentByProjectID busca os limit logs mais recentes de um projeto em ordem de sequência
+func (r *logRepository) GetRecentByProjectID(ctx context.Context, projectID uint, limit int) ([]*domain.ProjectLog, error) {
+	var entries []*domain.ProjectLog
+	err := r.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("sequence DESC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	// A consulta traz os mais recentes primeiro
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// DeleteByProjectID remove os logs de um projeto
+func (r *logRepository) DeleteByProjectID(ctx context.Context, projectID uint) error {
+	return r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&domain.ProjectLog{}).Error
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/phuslu/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // projectRepository implementa domain.ProjectRepository
@@ -31,7 +32,10 @@ func (r *projectRepository) Create(ctx context.Context, project *domain.Project)
 // GetByID busca um projeto por ID
 func (r *projectRepository) GetByID(ctx context.Context, id uint) (*domain.Project, error) {
 	var project domain.Project
-	err := r.db.WithContext(ctx).Preload("Template").First(&project, id).Error
+	err := r.db.WithContext(ctx).
+		Preload("Template").
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&project, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -48,9 +52,10 @@ func (r *projectRepository) GetAll(ctx context.Context) ([]*domain.Project, erro
 	return projects, err
 }
 
-// Update atualiza um projeto existente
+// Update atualiza um projeto existente. Template e etapas são gravados pelos
+// próprios repositórios e não são salvos junto com o projeto.
 func (r *projectRepository) Update(ctx context.Context, project *domain.Project) error {
-	return r.db.WithContext(ctx).Save(project).Error
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(project).Error
 }
 
 // Delete remove um projeto
@@ -58,6 +63,13 @@ func (r *projectRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&domain.Project{}, id).Error
 }
 
+// GetByStatus busca os projetos com qualquer um dos status informados
+func (r *projectRepository) GetByStatus(ctx context.Context, statuses ...string) ([]*domain.Project, error) {
+	var projects []*domain.Project
+	err := r.db.WithContext(ctx).Preload("Template").Where("status IN ?", statuses).Find(&projects).Error
+	return projects, err
+}
+
 // GetByName busca um projeto por nome
 func (r *projectRepository) GetByName(ctx context.Context, name string) (*domain.Project, error) {
 	var project domain.Project
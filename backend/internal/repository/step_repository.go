@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"template-manager-backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// stepRepository implementa domain.ProjectStepRepository
+type stepRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectStepRepository cria uma nova instância do repositório de etapas
+func NewProjectStepRepository(db *gorm.DB) domain.ProjectStepRepository {
+	return &stepRepository{db: db}
+}
+
+// Replace remove as etapas anteriores do projeto e grava as novas numa transação
+func (r *stepRepository) Replace(ctx context.Context, projectID uint, steps []domain.ProjectStep) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&domain.ProjectStep{}).Error; err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+		return tx.Create(&steps).Error
+	})
+}
+
+// Update atualiza uma etapa existente
+func (r *stepRepository) Update(ctx context.Context, step *domain.ProjectStep) error {
+	return r.db.WithContext(ctx).Save(step).Error
+}
+
+// DeleteByProjectID remove as etapas de um projeto
+func (r *stepRepository) DeleteByProjectID(ctx context.Context, projectID uint) error {
+	return r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&domain.ProjectStep{}).Error
+}
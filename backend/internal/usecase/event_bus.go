@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"sync"
+	"template-manager-backend/internal/domain"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// eventBufferSize é quantos eventos um inscrito pode ficar para trás antes de
+// ser desconectado
+const eventBufferSize = 64
+
+// EventBus distribui os eventos de domínio a todos os inscritos. Não guarda
+// histórico: cada inscrito recebe apenas os eventos publicados após a inscrição.
+type EventBus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	subscribers map[chan domain.DomainEvent]struct{}
+}
+
+// NewEventBus cria um EventBus sem inscritos
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[chan domain.DomainEvent]struct{}),
+	}
+}
+
+// Publish atribui ID e horário a um novo evento e o entrega aos inscritos. Um
+// inscrito com o buffer cheio é desconectado em vez de perder o evento em
+// silêncio, para que o cliente saiba que deve reconectar e recarregar.
+func (b *EventBus) Publish(eventType string, resourceID uint, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := domain.DomainEvent{
+		ID:         b.nextID,
+		Type:       eventType,
+		ResourceID: resourceID,
+		Data:       data,
+		Timestamp:  time.Now(),
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Str("event", eventType).Msg("event subscriber lagged, disconnecting")
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe retorna um canal que recebe os eventos publicados a partir de
+// agora e uma função que encerra a inscrição. O canal é fechado quando a
+// inscrição termina.
+func (b *EventBus) Subscribe() (<-chan domain.DomainEvent, func()) {
+	ch := make(chan domain.DomainEvent, eventBufferSize)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
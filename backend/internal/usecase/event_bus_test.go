@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"template-manager-backend/internal/domain"
+	"testing"
+	"time"
+)
+
+// receive aguarda o próximo evento do canal; ok é falso se o canal foi fechado
+func receive(t *testing.T, ch <-chan domain.DomainEvent) (domain.DomainEvent, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		return event, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a domain event")
+		return domain.DomainEvent{}, false
+	}
+}
+
+func TestEventBusPublish(t *testing.T) {
+	bus := NewEventBus()
+	// Eventos publicados antes da inscrição não são entregues
+	bus.Publish(domain.EventTemplateCreated, 1, nil)
+
+	ch, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+	bus.Publish(domain.EventProjectQueued, 7, domain.ProjectStatusChange{Name: "demo", Status: domain.ProjectStatusQueued})
+
+	event, ok := receive(t, ch)
+	if !ok {
+		t.Fatal("channel closed, want an event")
+	}
+	if event.ID != 2 || event.Type != domain.EventProjectQueued || event.ResourceID != 7 || event.Timestamp.IsZero() {
+		t.Fatalf("event = %+v, want project.queued #2 for resource 7", event)
+	}
+	if change, ok := event.Data.(domain.ProjectStatusChange); !ok || change.Name != "demo" {
+		t.Fatalf("data = %#v, want the published status change", event.Data)
+	}
+}
+
+func TestEventBusFansOutToEverySubscriber(t *testing.T) {
+	bus := NewEventBus()
+	var channels []<-chan domain.DomainEvent
+	for i := 0; i < 3; i++ {
+		ch, unsubscribe := bus.Subscribe()
+		defer unsubscribe()
+		channels = append(channels, ch)
+	}
+
+	bus.Publish(domain.EventTemplateCreated, 1, nil)
+	bus.Publish(domain.EventTemplateDeleted, 1, nil)
+
+	for i, ch := range channels {
+		for _, want := range []string{domain.EventTemplateCreated, domain.EventTemplateDeleted} {
+			event, ok := receive(t, ch)
+			if !ok || event.Type != want {
+				t.Fatalf("subscriber %d got %+v (open = %v), want %s", i, event, ok, want)
+			}
+		}
+	}
+}
+
+func TestEventBusDisconnectsLaggingSubscriber(t *testing.T) {
+	bus := NewEventBus()
+	slow, unsubscribeSlow := bus.Subscribe()
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := bus.Subscribe()
+	defer unsubscribeFast()
+
+	// O inscrito lento não lê nada e fica um evento além do buffer
+	for i := 1; i <= eventBufferSize+1; i++ {
+		bus.Publish(domain.EventProjectStatusChanged, uint(i), nil)
+		if event, ok := receive(t, fast); !ok || event.ResourceID != uint(i) {
+			t.Fatalf("fast subscriber got %+v (open = %v), want resource %d", event, ok, i)
+		}
+	}
+
+	// Os eventos que couberam no buffer são entregues antes do fechamento
+	for i := 1; i <= eventBufferSize; i++ {
+		if event, ok := receive(t, slow); !ok || event.ResourceID != uint(i) {
+			t.Fatalf("slow subscriber event %d = %+v (open = %v)", i, event, ok)
+		}
+	}
+	if event, ok := receive(t, slow); ok {
+		t.Fatalf("slow subscriber got %+v, want the channel closed", event)
+	}
+
+	// Encerrar uma inscrição já desconectada não fecha o canal de novo
+	unsubscribeSlow()
+	bus.Publish(domain.EventProjectDeleted, 1, nil)
+	if event, ok := receive(t, fast); !ok || event.Type != domain.EventProjectDeleted {
+		t.Fatalf("fast subscriber got %+v (open = %v) after the disconnect", event, ok)
+	}
+}
+
+func TestEventBusUnsubscribeClosesChannel(t *testing.T) {
+	bus := NewEventBus()
+	ch, unsubscribe := bus.Subscribe()
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := receive(t, ch); ok {
+		t.Fatal("channel open after unsubscribe")
+	}
+	// Publicar sem inscritos não bloqueia
+	bus.Publish(domain.EventTemplateCreated, 1, nil)
+}
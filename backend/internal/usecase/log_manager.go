@@ -1,99 +1,315 @@
 package usecase
 
 import (
+	"context"
 	"sync"
+	"template-manager-backend/internal/domain"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+const (
+	defaultLogMaxEntries = 1000
+	defaultLogTTL        = 10 * time.Minute
+	// logSweepInterval is the longest interval between evictions of expired streams
+	logSweepInterval = time.Minute
 )
 
-// LogManager stores logs per project and allows subscribers to receive updates.
+// LogManager stores structured log events per project and allows subscribers to
+// receive updates. Every event is persisted, so the history survives restarts;
+// streams only exist in memory while a project is producing logs and for the
+// retention TTL after it finishes.
 type LogManager struct {
-	mu      sync.Mutex
-	streams map[uint]*logStream
+	mu        sync.Mutex
+	streams   map[uint]*logStream
+	repo      domain.LogRepository
+	retention LogRetention
+}
+
+// LogRetention bounds the memory used by a LogManager. Events outside these
+// limits are still served from storage.
+type LogRetention struct {
+	// MaxEntries is the number of most recent events kept in memory per project
+	MaxEntries int
+	// TTL is how long a closed stream stays in memory before it is evicted
+	TTL time.Duration
 }
 
 type logStream struct {
 	mu          sync.Mutex
-	logs        []string
-	subscribers []chan string
+	logs        []domain.ProjectLog
+	sequence    int
+	step        string
+	subscribers []*LogSubscription
 	closed      bool
+	closedAt    time.Time
 }
 
-// NewLogManager creates a new LogManager.
-func NewLogManager() *LogManager {
-	return &LogManager{streams: make(map[uint]*logStream)}
+// NewLogManager creates a new LogManager backed by repo. Zero retention values
+// use the defaults.
+func NewLogManager(repo domain.LogRepository, retention LogRetention) *LogManager {
+	if retention.MaxEntries < 1 {
+		retention.MaxEntries = defaultLogMaxEntries
+	}
+	if retention.TTL <= 0 {
+		retention.TTL = defaultLogTTL
+	}
+	return &LogManager{
+		streams:   make(map[uint]*logStream),
+		repo:      repo,
+		retention: retention,
+	}
 }
 
-func (m *LogManager) getStream(id uint) *logStream {
+// Start evicts expired streams in the background until ctx is cancelled.
+func (m *LogManager) Start(ctx context.Context) {
+	interval := logSweepInterval
+	if m.retention.TTL < interval {
+		interval = m.retention.TTL
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.evictExpired()
+			}
+		}
+	}()
+}
+
+// evictExpired removes from memory the streams closed for longer than the TTL.
+// Their events remain in storage.
+func (m *LogManager) evictExpired() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	for id, s := range m.streams {
+		s.mu.Lock()
+		expired := s.closed && time.Since(s.closedAt) >= m.retention.TTL
+		s.mu.Unlock()
+		if expired {
+			delete(m.streams, id)
+			log.Debug().Uint("project_id", id).Msg("project log evicted from memory")
+		}
+	}
+}
+
+// getStream returns the in-memory stream for the project, seeding it with the
+// most recent stored events so that sequences continue after a restart. Older
+// events are read from storage on demand.
+func (m *LogManager) getStream(id uint) *logStream {
+	m.mu.Lock()
 	s, ok := m.streams[id]
-	if !ok {
-		s = &logStream{}
-		m.streams[id] = s
+	m.mu.Unlock()
+	if ok {
+		return s
+	}
+
+	recent, err := m.repo.GetRecentByProjectID(context.Background(), id, m.retention.MaxEntries)
+	if err != nil {
+		log.Error().Err(err).Uint("project_id", id).Msg("failed to load project logs")
 	}
+	s = &logStream{}
+	for _, entry := range recent {
+		s.logs = append(s.logs, *entry)
+		s.sequence = entry.Sequence
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Another goroutine may have created the stream while the history was loading
+	if existing, ok := m.streams[id]; ok {
+		return existing
+	}
+	m.streams[id] = s
 	return s
 }
 
-// Append adds a message to the project log and broadcasts to subscribers.
+// history loads the stored logs of the project with a sequence greater than after.
+func (m *LogManager) history(id uint, after int) []*domain.ProjectLog {
+	entries, err := m.repo.GetByProjectID(context.Background(), id, after)
+	if err != nil {
+		log.Error().Err(err).Uint("project_id", id).Msg("failed to load project logs")
+		return nil
+	}
+	return entries
+}
+
+// Append adds an info message to the project log and broadcasts to subscribers.
 func (m *LogManager) Append(id uint, msg string) {
+	m.Log(id, domain.LogLevelInfo, msg)
+}
+
+// Log adds a message with the given level and broadcasts to subscribers.
+func (m *LogManager) Log(id uint, level, msg string) {
+	m.Emit(id, domain.ProjectLog{Level: level, Message: msg})
+}
+
+// Emit persists an event and broadcasts it to subscribers. The sequence and
+// timestamp are assigned here; type and level default to log and info, and
+// the step defaults to the step currently running for the project.
+func (m *LogManager) Emit(id uint, event domain.ProjectLog) {
 	s := m.getStream(id)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.closed {
 		return
 	}
-	s.logs = append(s.logs, msg)
+
+	s.sequence++
+	event.ID = 0
+	event.ProjectID = id
+	event.Sequence = s.sequence
+	event.CreatedAt = time.Now()
+	if event.Type == "" {
+		event.Type = domain.LogEventLog
+	}
+	if event.Level == "" {
+		event.Level = domain.LogLevelInfo
+	}
+	if event.Step == "" {
+		event.Step = s.step
+	}
+	if err := m.repo.Create(context.Background(), &event); err != nil {
+		log.Error().Err(err).Uint("project_id", id).Msg("failed to persist project log")
+	}
+
+	s.logs = append(s.logs, event)
+	s.trim(m.retention.MaxEntries)
 	for _, sub := range s.subscribers {
-		select {
-		case sub <- msg:
-		default:
+		sub.notify()
+	}
+}
+
+// trim drops the oldest events so that at most max stay in memory.
+func (s *logStream) trim(max int) {
+	if over := len(s.logs) - max; over > 0 {
+		s.logs = s.logs[over:]
+	}
+}
+
+// backlog returns the events with a sequence greater than after, reading from
+// storage when the oldest of them were already trimmed from memory. The caller
+// must hold s.mu.
+func (m *LogManager) backlog(id uint, s *logStream, after int) []domain.ProjectLog {
+	var events []domain.ProjectLog
+	if len(s.logs) > 0 && s.logs[0].Sequence > after+1 {
+		for _, entry := range m.history(id, after) {
+			events = append(events, *entry)
 		}
+		return events
 	}
+	for _, l := range s.logs {
+		if l.Sequence > after {
+			events = append(events, l)
+		}
+	}
+	return events
 }
 
-// Subscribe returns a channel that receives future log messages for the project.
-// Existing logs are sent immediately in a separate goroutine.
-func (m *LogManager) Subscribe(id uint) <-chan string {
+// SetStep records the step currently running for the project, so that events
+// emitted without a step are attributed to it. An empty step clears it.
+func (m *LogManager) SetStep(id uint, step string) {
 	s := m.getStream(id)
-	ch := make(chan string, 10)
 	s.mu.Lock()
-	if s.closed {
-		close(ch)
-		s.mu.Unlock()
-		return ch
+	defer s.mu.Unlock()
+	s.step = step
+}
+
+// Subscribe returns a subscription to the log events of the project with a
+// sequence greater than after, followed by future events. Without an
+// in-memory stream nothing is producing logs, so the subscription replays the
+// stored history and ends.
+func (m *LogManager) Subscribe(id uint, after int) *LogSubscription {
+	sub := &LogSubscription{
+		m:      m,
+		id:     id,
+		cursor: after,
+		ready:  make(chan struct{}, 1),
 	}
-	s.subscribers = append(s.subscribers, ch)
-	logs := append([]string(nil), s.logs...)
-	s.mu.Unlock()
-	go func() {
-		for _, l := range logs {
-			ch <- l
+
+	m.mu.Lock()
+	s, ok := m.streams[id]
+	m.mu.Unlock()
+	if !ok {
+		for _, entry := range m.history(id, after) {
+			sub.pending = append(sub.pending, *entry)
 		}
-	}()
-	return ch
+		return sub
+	}
+
+	sub.s = s
+	s.mu.Lock()
+	s.subscribers = append(s.subscribers, sub)
+	s.mu.Unlock()
+	return sub
 }
 
-// Close marks the project log as closed and closes all subscriber channels.
+// Close marks the project log as closed. Subscribers receive the remaining
+// events and then ErrLogClosed.
 func (m *LogManager) Close(id uint) {
 	s := m.getStream(id)
 	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.closed {
-		s.mu.Unlock()
 		return
 	}
 	s.closed = true
-	subs := s.subscribers
-	s.subscribers = nil
-	s.mu.Unlock()
-	for _, ch := range subs {
-		close(ch)
+	s.closedAt = time.Now()
+	for _, sub := range s.subscribers {
+		sub.notify()
 	}
 }
 
-// GetLogs returns a copy of all logs for the project.
-func (m *LogManager) GetLogs(id uint) []string {
-	s := m.getStream(id)
+// Reopen allows a closed project log to receive messages again, keeping the
+// existing history. It is used when a failed creation is retried.
+func (m *LogManager) Reopen(id uint) {
+	for {
+		s := m.getStream(id)
+		// The stream is reopened under m.mu so that evictExpired cannot drop it
+		// in between; if it was evicted after the lookup, a new one is loaded.
+		m.mu.Lock()
+		if m.streams[id] != s {
+			m.mu.Unlock()
+			continue
+		}
+		s.mu.Lock()
+		s.closed = false
+		s.step = ""
+		s.mu.Unlock()
+		m.mu.Unlock()
+		return
+	}
+}
+
+// Delete closes the project log and removes it from memory and storage.
+func (m *LogManager) Delete(id uint) error {
+	m.Close(id)
+	m.mu.Lock()
+	delete(m.streams, id)
+	m.mu.Unlock()
+	return m.repo.DeleteByProjectID(context.Background(), id)
+}
+
+// GetLogs returns a copy of all logs for the project, reading the stored
+// history when the events are not in memory.
+func (m *LogManager) GetLogs(id uint) []domain.ProjectLog {
+	m.mu.Lock()
+	s, ok := m.streams[id]
+	m.mu.Unlock()
+	if !ok {
+		var logs []domain.ProjectLog
+		for _, entry := range m.history(id, 0) {
+			logs = append(logs, *entry)
+		}
+		return logs
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	logs := append([]string(nil), s.logs...)
-	return logs
+	return m.backlog(id, s, 0)
 }
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"template-manager-backend/internal/domain"
+	"testing"
+	"time"
+)
+
+// memoryLogRepository is an in-memory domain.LogRepository.
+type memoryLogRepository struct {
+	mu      sync.Mutex
+	entries []domain.ProjectLog
+}
+
+func (r *memoryLogRepository) Create(ctx context.Context, entry *domain.ProjectLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = uint(len(r.entries) + 1)
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *memoryLogRepository) GetByProjectID(ctx context.Context, projectID uint, after int) ([]*domain.ProjectLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var entries []*domain.ProjectLog
+	for _, entry := range r.entries {
+		if entry.ProjectID == projectID && entry.Sequence > after {
+			entry := entry
+			entries = append(entries, &entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *memoryLogRepository) GetRecentByProjectID(ctx context.Context, projectID uint, limit int) ([]*domain.ProjectLog, error) {
+	entries, _ := r.GetByProjectID(ctx, projectID, 0)
+	if over := len(entries) - limit; over > 0 {
+		entries = entries[over:]
+	}
+	return entries, nil
+}
+
+func (r *memoryLogRepository) DeleteByProjectID(ctx context.Context, projectID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.entries[:0]
+	for _, entry := range r.entries {
+		if entry.ProjectID != projectID {
+			kept = append(kept, entry)
+		}
+	}
+	r.entries = kept
+	return nil
+}
+
+// remove deletes the stored events of the project with a sequence in [from, to].
+func (r *memoryLogRepository) remove(projectID uint, from, to int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.entries[:0]
+	for _, entry := range r.entries {
+		if entry.ProjectID != projectID || entry.Sequence < from || entry.Sequence > to {
+			kept = append(kept, entry)
+		}
+	}
+	r.entries = kept
+}
+
+// nextEvent waits for the next event of the subscription.
+func nextEvent(t *testing.T, sub *LogSubscription) (domain.ProjectLog, error) {
+	t.Helper()
+	for {
+		event, ok, err := sub.Next()
+		if err != nil || ok {
+			return event, err
+		}
+		select {
+		case <-sub.Ready():
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a log event")
+		}
+	}
+}
+
+func TestLogSubscriptionSlowReaderCatchesUpFromStorage(t *testing.T) {
+	const (
+		maxEntries = 5
+		total      = 200
+	)
+	m := NewLogManager(&memoryLogRepository{}, LogRetention{MaxEntries: maxEntries})
+	m.Append(1, "message 1")
+	sub := m.Subscribe(1, 0)
+	defer sub.Close()
+
+	// The writer runs ahead of the reader by more than MaxEntries events, so
+	// part of what the reader needs is only left in storage
+	ahead := make(chan struct{})
+	go func() {
+		for i := 2; i <= total; i++ {
+			m.Append(1, fmt.Sprintf("message %d", i))
+			if i == 4*maxEntries {
+				close(ahead)
+			}
+		}
+		m.Close(1)
+	}()
+
+	for i := 1; i <= total; i++ {
+		event, err := nextEvent(t, sub)
+		if err != nil {
+			t.Fatalf("event %d: %v", i, err)
+		}
+		if event.Type != domain.LogEventLog || event.Sequence != i || event.Message != fmt.Sprintf("message %d", i) {
+			t.Fatalf("event %d = %+v, want message %d in order", i, event, i)
+		}
+		if i == 1 {
+			<-ahead
+		}
+	}
+	if _, err := nextEvent(t, sub); !errors.Is(err, ErrLogClosed) {
+		t.Fatalf("after the last event err = %v, want ErrLogClosed", err)
+	}
+}
+
+func TestLogSubscriptionReportsEventsMissingFromStorage(t *testing.T) {
+	repo := &memoryLogRepository{}
+	m := NewLogManager(repo, LogRetention{MaxEntries: 3})
+	for i := 1; i <= 10; i++ {
+		m.Append(1, fmt.Sprintf("message %d", i))
+	}
+	m.Close(1)
+	// Memory holds 8 to 10; 2 to 6 are gone from storage too
+	repo.remove(1, 2, 6)
+
+	sub := m.Subscribe(1, 0)
+	defer sub.Close()
+
+	want := []struct {
+		eventType string
+		sequence  int
+	}{
+		{domain.LogEventLog, 1},
+		{domain.LogEventLagged, 6},
+		{domain.LogEventLog, 7},
+		{domain.LogEventLog, 8},
+		{domain.LogEventLog, 9},
+		{domain.LogEventLog, 10},
+	}
+	for _, w := range want {
+		event, err := nextEvent(t, sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != w.eventType || event.Sequence != w.sequence {
+			t.Fatalf("got %s %d, want %s %d", event.Type, event.Sequence, w.eventType, w.sequence)
+		}
+		if event.Type == domain.LogEventLagged && event.Message != "5 log events are no longer available" {
+			t.Fatalf("lagged message = %q", event.Message)
+		}
+	}
+	if _, err := nextEvent(t, sub); !errors.Is(err, ErrLogClosed) {
+		t.Fatalf("err = %v, want ErrLogClosed", err)
+	}
+}
+
+func TestLogSubscriptionEndsWhenClosedWhileWaiting(t *testing.T) {
+	m := NewLogManager(&memoryLogRepository{}, LogRetention{})
+	m.Append(1, "started")
+	sub := m.Subscribe(1, 1)
+	defer sub.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			_, ok, err := sub.Next()
+			if err != nil || ok {
+				done <- err
+				return
+			}
+			<-sub.Ready()
+		}
+	}()
+
+	m.Close(1)
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrLogClosed) {
+			t.Fatalf("err = %v, want ErrLogClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber was not woken by Close")
+	}
+}
+
+func TestLogSubscriptionContinuesAfterReopen(t *testing.T) {
+	m := NewLogManager(&memoryLogRepository{}, LogRetention{})
+	m.Append(1, "first attempt")
+	sub := m.Subscribe(1, 1)
+	defer sub.Close()
+
+	if _, ok, err := sub.Next(); ok || err != nil {
+		t.Fatalf("Next() = %v, %v, want no event yet", ok, err)
+	}
+	// A retry reopens the log before the waiting subscriber wakes up
+	m.Close(1)
+	m.Reopen(1)
+	m.Append(1, "retry")
+
+	event, err := nextEvent(t, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Sequence != 2 || event.Message != "retry" {
+		t.Fatalf("event = %+v, want the retry message", event)
+	}
+}
+
+func TestLogManagerReopenRacingEviction(t *testing.T) {
+	m := NewLogManager(&memoryLogRepository{}, LogRetention{TTL: time.Nanosecond})
+	for i := 0; i < 200; i++ {
+		m.Append(1, fmt.Sprintf("attempt %d", i))
+		m.Close(1)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.evictExpired()
+		}()
+		go func() {
+			defer wg.Done()
+			m.Reopen(1)
+		}()
+		wg.Wait()
+
+		// The reopened stream must be the one registered, otherwise it is an
+		// orphan that no later event or subscriber reaches
+		m.mu.Lock()
+		s, ok := m.streams[1]
+		m.mu.Unlock()
+		if !ok {
+			t.Fatalf("iteration %d: reopened stream is not registered", i)
+		}
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			t.Fatalf("iteration %d: registered stream is still closed", i)
+		}
+	}
+
+	sub := m.Subscribe(1, 200)
+	defer sub.Close()
+	m.Append(1, "after reopen")
+	event, err := nextEvent(t, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Message != "after reopen" {
+		t.Fatalf("event = %+v, want the message emitted after reopen", event)
+	}
+}
+
+func TestLogManagerSeedsStreamFromRecentEvents(t *testing.T) {
+	repo := &memoryLogRepository{}
+	first := NewLogManager(repo, LogRetention{MaxEntries: 3})
+	for i := 1; i <= 10; i++ {
+		first.Append(1, fmt.Sprintf("message %d", i))
+	}
+
+	// A new manager, as after a restart, continues the sequence
+	m := NewLogManager(repo, LogRetention{MaxEntries: 3})
+	m.Append(1, "message 11")
+	logs := m.GetLogs(1)
+	if len(logs) != 11 || logs[10].Sequence != 11 {
+		t.Fatalf("got %d logs ending at %d, want 11", len(logs), logs[len(logs)-1].Sequence)
+	}
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"template-manager-backend/internal/domain"
+	"time"
+)
+
+// ErrLogClosed is returned by LogSubscription.Next once the project log is
+// closed and every event has been delivered.
+var ErrLogClosed = errors.New("project log closed")
+
+// LogSubscription delivers the log events of a project in sequence order and
+// without gaps. Each subscription keeps its own cursor into the stream buffer,
+// so a slow reader never blocks the producer nor loses events: events already
+// trimmed from memory are read back from storage, and events missing from
+// storage as well are reported with a single lagged event.
+type LogSubscription struct {
+	m  *LogManager
+	id uint
+	// s is nil when the project had no in-memory stream; the subscription
+	// then only replays the stored history
+	s       *logStream
+	cursor  int
+	pending []domain.ProjectLog
+	ready   chan struct{}
+}
+
+// Ready returns a channel that is signalled when new events may be available.
+func (sub *LogSubscription) Ready() <-chan struct{} {
+	return sub.ready
+}
+
+// Next returns the event following the cursor without blocking. ok is false
+// when there is no new event yet; wait on Ready before calling it again.
+// ErrLogClosed means the log was closed and everything was delivered.
+func (sub *LogSubscription) Next() (event domain.ProjectLog, ok bool, err error) {
+	if len(sub.pending) > 0 {
+		event = sub.pending[0]
+		sub.pending = sub.pending[1:]
+		sub.cursor = event.Sequence
+		return event, true, nil
+	}
+	if sub.s == nil {
+		return event, false, ErrLogClosed
+	}
+
+	s := sub.s
+	s.mu.Lock()
+	if sub.cursor >= s.sequence {
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return event, false, ErrLogClosed
+		}
+		return event, false, nil
+	}
+	i := sort.Search(len(s.logs), func(i int) bool {
+		return s.logs[i].Sequence > sub.cursor
+	})
+	if i == len(s.logs) {
+		// Events were produced but none is in memory
+		s.mu.Unlock()
+		return sub.catchUp(s.sequence + 1)
+	}
+	next := s.logs[i]
+	s.mu.Unlock()
+
+	switch {
+	case next.Sequence == sub.cursor+1:
+		sub.cursor = next.Sequence
+		return next, true, nil
+	case i == 0:
+		// The events before next were trimmed from memory
+		return sub.catchUp(next.Sequence)
+	default:
+		return sub.lagged(next.Sequence - 1), true, nil
+	}
+}
+
+// catchUp loads from storage the events between the cursor and before, which
+// are no longer in memory, and returns the first of them. Loading stops at the
+// first gap in storage, which the following call reports as lagged.
+func (sub *LogSubscription) catchUp(before int) (domain.ProjectLog, bool, error) {
+	for _, entry := range sub.m.history(sub.id, sub.cursor) {
+		if entry.Sequence >= before {
+			break
+		}
+		if n := len(sub.pending); n > 0 && entry.Sequence != sub.pending[n-1].Sequence+1 {
+			break
+		}
+		sub.pending = append(sub.pending, *entry)
+	}
+	if len(sub.pending) == 0 {
+		return sub.lagged(before - 1), true, nil
+	}
+	if first := sub.pending[0].Sequence; first > sub.cursor+1 {
+		return sub.lagged(first - 1), true, nil
+	}
+	return sub.Next()
+}
+
+// lagged moves the cursor to upto and returns the event that reports the
+// events skipped on the way.
+func (sub *LogSubscription) lagged(upto int) domain.ProjectLog {
+	skipped := upto - sub.cursor
+	sub.cursor = upto
+	return domain.ProjectLog{
+		ProjectID: sub.id,
+		Sequence:  upto,
+		Type:      domain.LogEventLagged,
+		Level:     domain.LogLevelWarn,
+		Message:   fmt.Sprintf("%d log events are no longer available", skipped),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (sub *LogSubscription) Close() {
+	if sub.s == nil {
+		return
+	}
+	s := sub.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, other := range s.subscribers {
+		if other == sub {
+			s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// notify wakes the subscription without blocking; a pending signal already
+// covers any number of new events.
+func (sub *LogSubscription) notify() {
+	select {
+	case sub.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,416 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/internal/repository"
+	"template-manager-backend/pkg/database"
+	"template-manager-backend/pkg/gitprovider"
+	"template-manager-backend/pkg/localgit"
+	"testing"
+)
+
+// runGit executa o git em dir e retorna a saída sem espaços nas pontas
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// writeFiles cria os arquivos em dir, indexados pelo caminho separado por barras
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// creationFixture reúne um template git local, o banco de dados e o provedor
+// local usados para executar processProjectCreation de ponta a ponta
+type creationFixture struct {
+	uc             *ProjectUseCase
+	providers      *gitprovider.Registry
+	local          domain.GitService
+	templateDir    string
+	templateCommit string
+	reposDir       string
+}
+
+func newCreationFixture(t *testing.T) *creationFixture {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	templateDir := t.TempDir()
+	runGit(t, templateDir, "init", "--initial-branch=main")
+	writeFiles(t, templateDir, map[string]string{
+		"README.md":              "# {{ .ProjectName }}\n",
+		"src/{{ProjectName}}.go": "// {{ .Greeting }} from {{ .TemplateName }}\n",
+	})
+	runGit(t, templateDir, "add", ".")
+	runGit(t, templateDir, "commit", "-m", "template")
+
+	db, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reposDir := t.TempDir()
+	local, err := localgit.NewGitService(reposDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	providers := gitprovider.NewRegistry(localgit.ProviderName)
+	providers.Register(localgit.ProviderName, local)
+
+	uc := NewProjectUseCase(
+		repository.NewProjectRepository(db),
+		repository.NewTemplateRepository(db),
+		repository.NewJobRepository(db),
+		repository.NewProjectStepRepository(db),
+		repository.NewAuditRepository(db),
+		NewLogManager(repository.NewLogRepository(db), LogRetention{}),
+		NewEventBus(),
+		providers,
+	)
+	return &creationFixture{
+		uc:             uc,
+		providers:      providers,
+		local:          local,
+		templateDir:    templateDir,
+		templateCommit: runGit(t, templateDir, "rev-parse", "HEAD"),
+		reposDir:       reposDir,
+	}
+}
+
+// createProject grava um template com a política de rollback informada e um
+// projeto "demo" na fila, e retorna o projeto com o template carregado
+func (f *creationFixture) createProject(t *testing.T, policy string) *domain.Project {
+	t.Helper()
+	ctx := context.Background()
+	template := &domain.Template{Name: "service", GitURL: f.templateDir, RollbackPolicy: policy}
+	if err := f.uc.templateRepo.Create(ctx, template); err != nil {
+		t.Fatal(err)
+	}
+	created := &domain.Project{
+		Name:       "demo",
+		TemplateID: template.ID,
+		Status:     domain.ProjectStatusQueued,
+		Provider:   localgit.ProviderName,
+		Variables:  map[string]any{"Greeting": "hello"},
+	}
+	if err := f.uc.projectRepo.Create(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	project, err := f.uc.projectRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+// stored relê o projeto do banco
+func (f *creationFixture) stored(t *testing.T, id uint) *domain.Project {
+	t.Helper()
+	project, err := f.uc.projectRepo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+// bareRepo é o caminho do repositório criado pelo provedor local
+func (f *creationFixture) bareRepo() string {
+	return filepath.Join(f.reposDir, "demo.git")
+}
+
+func TestProcessProjectCreationWithLocalProvider(t *testing.T) {
+	f := newCreationFixture(t)
+	project := f.createProject(t, domain.RollbackPolicyDelete)
+
+	if err := f.uc.processProjectCreation(context.Background(), project, &project.Template); err != nil {
+		t.Fatalf("processProjectCreation: %v\nlogs: %+v", err, f.uc.logs.GetLogs(project.ID))
+	}
+
+	stored := f.stored(t, project.ID)
+	bareRepo := f.bareRepo()
+	if want := "file://" + filepath.ToSlash(bareRepo); stored.GitURL != want {
+		t.Errorf("GitURL = %q, want %q", stored.GitURL, want)
+	}
+	if stored.Status != domain.ProjectStatusReady || stored.FailedStep != "" {
+		t.Errorf("status = %q, failed step = %q, want ready", stored.Status, stored.FailedStep)
+	}
+	if stored.TemplateCommit != f.templateCommit {
+		t.Errorf("TemplateCommit = %q, want %q", stored.TemplateCommit, f.templateCommit)
+	}
+	if len(stored.Steps) != len(domain.ProjectSteps()) {
+		t.Errorf("recorded %d steps, want %d", len(stored.Steps), len(domain.ProjectSteps()))
+	}
+	for _, step := range stored.Steps {
+		if step.Status != domain.StepStatusDone {
+			t.Errorf("step %s = %s, want done", step.Name, step.Status)
+		}
+	}
+
+	// O repositório bare contém os arquivos renderizados num único commit novo
+	files := runGit(t, bareRepo, "ls-tree", "-r", "--name-only", "HEAD")
+	if files != "README.md\nsrc/demo.go" {
+		t.Errorf("files in the repository = %q", files)
+	}
+	if readme := runGit(t, bareRepo, "show", "HEAD:README.md"); readme != "# demo" {
+		t.Errorf("README.md = %q", readme)
+	}
+	if source := runGit(t, bareRepo, "show", "HEAD:src/demo.go"); source != "// hello from service" {
+		t.Errorf("src/demo.go = %q", source)
+	}
+	if commits := runGit(t, bareRepo, "rev-list", "--count", "HEAD"); commits != "1" {
+		t.Errorf("commits = %s, want the template history cleared", commits)
+	}
+}
+
+// cancellingService cancela a criação logo depois do push, antes de o projeto
+// ser marcado como pronto
+type cancellingService struct {
+	domain.GitService
+	cancel context.CancelCauseFunc
+}
+
+func (s *cancellingService) PushToRepository(ctx context.Context, localPath, repoURL string) error {
+	err := s.GitService.PushToRepository(ctx, localPath, repoURL)
+	s.cancel(ErrProjectCancelled)
+	return err
+}
+
+func TestCancelledCreationFollowsRollbackPolicy(t *testing.T) {
+	tests := []struct {
+		policy     string
+		remoteKept bool
+	}{
+		{domain.RollbackPolicyKeep, true},
+		{domain.RollbackPolicyDelete, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			f := newCreationFixture(t)
+			project := f.createProject(t, tt.policy)
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			f.providers.Register(localgit.ProviderName, &cancellingService{GitService: f.local, cancel: cancel})
+
+			err := f.uc.processProjectCreation(ctx, project, &project.Template)
+			if err == nil {
+				t.Fatal("processProjectCreation() = nil error, want the cancellation")
+			}
+
+			stored := f.stored(t, project.ID)
+			if stored.Status != domain.ProjectStatusCancelled {
+				t.Errorf("status = %q, want cancelled", stored.Status)
+			}
+			_, statErr := os.Stat(f.bareRepo())
+			if kept := statErr == nil; kept != tt.remoteKept {
+				t.Errorf("remote repository kept = %v, want %v", kept, tt.remoteKept)
+			}
+			if kept := stored.GitURL != ""; kept != tt.remoteKept {
+				t.Errorf("GitURL = %q, want it kept = %v", stored.GitURL, tt.remoteKept)
+			}
+		})
+	}
+}
+
+// failingStatusRepository falha toda gravação de um projeto com o status informado
+type failingStatusRepository struct {
+	domain.ProjectRepository
+	status string
+}
+
+func (r *failingStatusRepository) Update(ctx context.Context, project *domain.Project) error {
+	if project.Status == r.status {
+		return errors.New("database is locked")
+	}
+	return r.ProjectRepository.Update(ctx, project)
+}
+
+func TestReadyStatusFailureKeepsPushedRepository(t *testing.T) {
+	f := newCreationFixture(t)
+	project := f.createProject(t, domain.RollbackPolicyDelete)
+	f.uc.projectRepo = &failingStatusRepository{ProjectRepository: f.uc.projectRepo, status: domain.ProjectStatusReady}
+
+	if err := f.uc.processProjectCreation(context.Background(), project, &project.Template); err == nil {
+		t.Fatal("processProjectCreation() = nil error, want the status write failure")
+	}
+
+	if _, err := os.Stat(f.bareRepo()); err != nil {
+		t.Fatalf("pushed repository was rolled back: %v", err)
+	}
+	stored := f.stored(t, project.ID)
+	if stored.Status != domain.ProjectStatusPushing || stored.GitURL == "" {
+		t.Errorf("status = %q, GitURL = %q, want pushing with the repository recorded", stored.Status, stored.GitURL)
+	}
+	if stored.FailedStep != "" {
+		t.Errorf("FailedStep = %q, want none", stored.FailedStep)
+	}
+}
+
+// interruptedJob grava um job em execução para o projeto, como se o servidor
+// tivesse parado durante a criação
+func (f *creationFixture) interruptedJob(t *testing.T, project *domain.Project, attempts int) *domain.ProjectJob {
+	t.Helper()
+	job := &domain.ProjectJob{
+		ProjectID: project.ID,
+		Provider:  project.Provider,
+		Status:    domain.JobStatusRunning,
+		Attempts:  attempts,
+	}
+	if err := f.uc.jobRepo.Create(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestRecoveredJobClonesRecordedCommit(t *testing.T) {
+	f := newCreationFixture(t)
+	ctx := context.Background()
+	project := f.createProject(t, domain.RollbackPolicyDelete)
+	project.TemplateCommit = f.templateCommit
+	if err := f.uc.setStatus(ctx, project, domain.ProjectStatusCloning); err != nil {
+		t.Fatal(err)
+	}
+	f.interruptedJob(t, project, 1)
+
+	// O branch do template avança enquanto o servidor está parado
+	writeFiles(t, f.templateDir, map[string]string{"README.md": "# changed\n"})
+	runGit(t, f.templateDir, "commit", "-am", "change")
+
+	if err := f.uc.recoverJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	job, err := f.uc.jobRepo.GetLatestByProjectID(ctx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != domain.JobStatusPending {
+		t.Fatalf("job status = %q, want pending", job.Status)
+	}
+
+	project = f.stored(t, project.ID)
+	if err := f.uc.processProjectCreation(ctx, project, &project.Template); err != nil {
+		t.Fatalf("processProjectCreation: %v", err)
+	}
+	if stored := f.stored(t, project.ID); stored.TemplateCommit != f.templateCommit {
+		t.Errorf("TemplateCommit = %q, want the recorded %q", stored.TemplateCommit, f.templateCommit)
+	}
+	if readme := runGit(t, f.bareRepo(), "show", "HEAD:README.md"); readme != "# demo" {
+		t.Errorf("README.md = %q, want the content of the recorded commit", readme)
+	}
+}
+
+func TestAbandonedJobRecordsInterruptedStep(t *testing.T) {
+	f := newCreationFixture(t)
+	ctx := context.Background()
+	project := f.createProject(t, domain.RollbackPolicyDelete)
+	if err := f.uc.resetSteps(ctx, project); err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []string{domain.ProjectStepClone, domain.ProjectStepClearHistory} {
+		f.uc.beginStep(ctx, project, step)
+		f.uc.endStep(ctx, project, step, domain.StepStatusDone, "")
+	}
+	f.uc.beginStep(ctx, project, domain.ProjectStepRender)
+	f.interruptedJob(t, project, maxJobAttempts)
+
+	if err := f.uc.recoverJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := f.stored(t, project.ID)
+	if stored.Status != domain.ProjectStatusError || stored.FailedStep != domain.ProjectStepRender {
+		t.Fatalf("status = %q, failed step = %q, want error at %s", stored.Status, stored.FailedStep, domain.ProjectStepRender)
+	}
+	for _, step := range stored.Steps {
+		if step.Name == domain.ProjectStepRender && step.Status != domain.StepStatusFailed {
+			t.Errorf("step %s = %s, want failed", step.Name, step.Status)
+		}
+	}
+	job, err := f.uc.jobRepo.GetLatestByProjectID(ctx, project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != domain.JobStatusFailed {
+		t.Errorf("job status = %q, want failed", job.Status)
+	}
+}
+
+// recordingAuditRepository guarda em memória as entradas de auditoria
+type recordingAuditRepository struct {
+	entries []domain.AuditEntry
+}
+
+func (r *recordingAuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func TestDeleteProjectIsAudited(t *testing.T) {
+	tests := []struct {
+		name       string
+		pushed     bool
+		remote     string
+		wantRemote string
+		remoteKept bool
+	}{
+		{"keep", true, domain.RollbackPolicyKeep, domain.RollbackPolicyKeep, true},
+		{"default keeps", true, "", domain.RollbackPolicyKeep, true},
+		{"delete", true, domain.RollbackPolicyDelete, domain.RollbackPolicyDelete, false},
+		{"without remote", false, domain.RollbackPolicyDelete, domain.AuditRemoteNone, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCreationFixture(t)
+			ctx := context.Background()
+			audit := &recordingAuditRepository{}
+			f.uc.auditRepo = audit
+			project := f.createProject(t, domain.RollbackPolicyDelete)
+			if tt.pushed {
+				if err := f.uc.processProjectCreation(ctx, project, &project.Template); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				f.uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusCancelled)
+			}
+
+			if err := f.uc.DeleteProject(ctx, project.ID, tt.remote, project.Name); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(audit.entries) != 1 {
+				t.Fatalf("audit entries = %+v, want one", audit.entries)
+			}
+			entry := audit.entries[0]
+			if entry.Action != domain.AuditActionProjectDelete || entry.Remote != tt.wantRemote || entry.Result != domain.AuditResultSucceeded {
+				t.Errorf("audit entry = %+v, want a succeeded %s with remote %s", entry, domain.AuditActionProjectDelete, tt.wantRemote)
+			}
+			_, statErr := os.Stat(f.bareRepo())
+			if kept := statErr == nil; kept != tt.remoteKept {
+				t.Errorf("remote repository kept = %v, want %v", kept, tt.remoteKept)
+			}
+		})
+	}
+}
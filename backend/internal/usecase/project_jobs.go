@@ -0,0 +1,404 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"template-manager-backend/internal/domain"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+const (
+	// defaultJobWorkers é usado quando QueueConfig não define Workers
+	defaultJobWorkers = 4
+	// jobPollInterval é o intervalo em que workers ociosos consultam a fila
+	jobPollInterval = 5 * time.Second
+	// maxJobAttempts limita quantas vezes um job interrompido é retomado
+	maxJobAttempts = 3
+)
+
+// ErrProjectCancelled é a causa do cancelamento do contexto de um job
+// interrompido por CancelProject
+var ErrProjectCancelled = errors.New("project creation cancelled")
+
+// QueueConfig define os limites de concorrência da fila de criação
+type QueueConfig struct {
+	// Workers é o limite global de criações simultâneas
+	Workers int
+	// ProviderLimits limita as criações simultâneas por provedor; provedores
+	// ausentes são limitados apenas por Workers
+	ProviderLimits map[string]int
+}
+
+// Start recupera os jobs interrompidos por um reinício e inicia os workers da
+// fila de criação de projetos. Os workers param quando ctx é cancelado.
+func (uc *ProjectUseCase) Start(ctx context.Context, config QueueConfig) error {
+	if config.Workers < 1 {
+		config.Workers = defaultJobWorkers
+	}
+	uc.queue = config
+
+	if err := uc.recoverJobs(ctx); err != nil {
+		return err
+	}
+	for i := 0; i < config.Workers; i++ {
+		go uc.runWorker(ctx)
+	}
+	log.Info().Int("workers", config.Workers).Msg("project job workers started")
+	uc.announceQueuePositions(ctx)
+	return nil
+}
+
+// enqueue persiste um job pendente para o projeto e acorda um worker
+func (uc *ProjectUseCase) enqueue(ctx context.Context, project *domain.Project) error {
+	job := &domain.ProjectJob{
+		ProjectID: project.ID,
+		Provider:  project.Provider,
+		Status:    domain.JobStatusPending,
+	}
+	if err := uc.jobRepo.Create(ctx, job); err != nil {
+		return err
+	}
+	log.Info().Uint("job_id", job.ID).Uint("project_id", project.ID).Msg("job enqueued")
+
+	uc.notifyWorkers()
+	uc.announceQueuePositions(ctx)
+	return nil
+}
+
+// notifyWorkers acorda um worker ocioso sem bloquear
+func (uc *ProjectUseCase) notifyWorkers() {
+	select {
+	case uc.wake <- struct{}{}:
+	default:
+	}
+}
+
+// claimJob reivindica o próximo job cujo provedor ainda tem vaga e retorna o
+// contexto cancelável em que ele deve executar. A reserva da vaga acontece sob
+// o mesmo lock da consulta para não ultrapassar o limite.
+func (uc *ProjectUseCase) claimJob(ctx context.Context) (*domain.ProjectJob, context.Context, error) {
+	uc.queueMu.Lock()
+	defer uc.queueMu.Unlock()
+
+	var saturated []string
+	for provider, limit := range uc.queue.ProviderLimits {
+		if uc.running[provider] >= limit {
+			saturated = append(saturated, provider)
+		}
+	}
+	sort.Strings(saturated)
+
+	job, err := uc.jobRepo.ClaimNext(ctx, saturated)
+	if err != nil || job == nil {
+		return nil, nil, err
+	}
+	uc.running[job.Provider]++
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	uc.cancels[job.ProjectID] = cancel
+	return job, jobCtx, nil
+}
+
+// releaseJob libera a vaga do provedor e acorda um worker para a fila
+func (uc *ProjectUseCase) releaseJob(job *domain.ProjectJob) {
+	uc.queueMu.Lock()
+	uc.running[job.Provider]--
+	if cancel, ok := uc.cancels[job.ProjectID]; ok {
+		cancel(nil)
+		delete(uc.cancels, job.ProjectID)
+	}
+	uc.queueMu.Unlock()
+	uc.notifyWorkers()
+}
+
+// CancelProject interrompe a criação de um projeto. Um job em execução tem o
+// contexto cancelado, o que encerra os processos git, e o worker faz a
+// limpeza; um job ainda na fila é cancelado imediatamente e os projetos atrás
+// dele recebem a nova posição.
+func (uc *ProjectUseCase) CancelProject(ctx context.Context, id uint) error {
+	dequeued, err := uc.cancelJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dequeued {
+		uc.announceQueuePositions(ctx)
+	}
+	return nil
+}
+
+// cancelJob cancela o job do projeto e indica se ele foi retirado da fila
+func (uc *ProjectUseCase) cancelJob(ctx context.Context, id uint) (bool, error) {
+	// O status é lido sob o lock para não cancelar um projeto que
+	// processProjectCreation acabou de marcar como pronto
+	uc.queueMu.Lock()
+	defer uc.queueMu.Unlock()
+
+	project, err := uc.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if project == nil {
+		return false, errors.New("project not found")
+	}
+	if !domain.IsInProgress(project.Status) {
+		return false, errors.New("project is not being created")
+	}
+
+	if cancel, ok := uc.cancels[id]; ok {
+		log.Info().Uint("project_id", id).Msg("cancelling running job")
+		uc.logs.Log(id, domain.LogLevelWarn, "Cancelling project creation")
+		cancel(ErrProjectCancelled)
+		return false, nil
+	}
+
+	// Sem worker ativo, o job pendente é cancelado antes de ser reivindicado;
+	// o lock impede que claimJob o pegue durante a atualização
+	job, err := uc.jobRepo.GetLatestByProjectID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if job == nil || job.Status != domain.JobStatusPending {
+		return false, errors.New("project is not being created")
+	}
+	now := time.Now()
+	job.Status = domain.JobStatusCancelled
+	job.LastError = ErrProjectCancelled.Error()
+	job.FinishedAt = &now
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		return false, err
+	}
+	delete(uc.positions, id)
+
+	log.Info().Uint("job_id", job.ID).Uint("project_id", id).Msg("pending job cancelled")
+	uc.logs.Log(id, domain.LogLevelWarn, "Project creation cancelled")
+	uc.updateProjectStatus(ctx, id, domain.ProjectStatusCancelled)
+	uc.logs.Close(id)
+	return true, nil
+}
+
+// queuePositions retorna a posição de cada projeto que aguarda na fila, na
+// ordem em que claimJob reivindicaria os jobs
+func (uc *ProjectUseCase) queuePositions(ctx context.Context) (map[uint]int, error) {
+	pending, err := uc.jobRepo.GetByStatus(ctx, domain.JobStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.queueMu.Lock()
+	running := make(map[string]int, len(uc.running))
+	for provider, count := range uc.running {
+		running[provider] = count
+	}
+	uc.queueMu.Unlock()
+
+	positions := make(map[uint]int, len(pending))
+	for i, job := range claimOrder(pending, running, uc.queue.ProviderLimits) {
+		positions[job.ProjectID] = i + 1
+	}
+	return positions, nil
+}
+
+// claimOrder simula claimJob sobre os jobs pendentes, do mais antigo ao mais
+// novo: cada job reivindicado é o mais antigo cujo provedor tem vaga e ocupa
+// uma vaga. Quando só restam jobs de provedores saturados, considera-se que
+// termina um job do provedor do pendente mais antigo. running é alterado.
+func claimOrder(pending []*domain.ProjectJob, running, limits map[string]int) []*domain.ProjectJob {
+	remaining := append([]*domain.ProjectJob(nil), pending...)
+	order := make([]*domain.ProjectJob, 0, len(pending))
+	for len(remaining) > 0 {
+		next := -1
+		for i, job := range remaining {
+			if limit, limited := limits[job.Provider]; !limited || running[job.Provider] < limit {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			running[remaining[0].Provider]--
+			continue
+		}
+
+		job := remaining[next]
+		running[job.Provider]++
+		order = append(order, job)
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return order
+}
+
+// announceQueuePositions publica no log de cada projeto pendente a sua posição
+// na fila sempre que ela muda
+func (uc *ProjectUseCase) announceQueuePositions(ctx context.Context) {
+	positions, err := uc.queuePositions(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to compute queue positions")
+		return
+	}
+
+	uc.queueMu.Lock()
+	defer uc.queueMu.Unlock()
+	for projectID, position := range positions {
+		if uc.positions[projectID] != position {
+			uc.logs.Append(projectID, fmt.Sprintf("Queue position: %d", position))
+		}
+	}
+	uc.positions = positions
+}
+
+// recoverJobs devolve à fila os jobs que estavam em execução quando o servidor
+// parou e cria jobs para projetos que ficaram em "creating" sem job ativo
+func (uc *ProjectUseCase) recoverJobs(ctx context.Context) error {
+	running, err := uc.jobRepo.GetByStatus(ctx, domain.JobStatusRunning)
+	if err != nil {
+		return err
+	}
+	for _, job := range running {
+		if job.Attempts >= maxJobAttempts {
+			now := time.Now()
+			job.Status = domain.JobStatusFailed
+			job.LastError = "job interrupted too many times"
+			job.FinishedAt = &now
+			if err := uc.jobRepo.Update(ctx, job); err != nil {
+				return err
+			}
+			uc.abandonProject(ctx, job.ProjectID, job.LastError)
+			uc.logs.Close(job.ProjectID)
+			log.Warn().Uint("job_id", job.ID).Uint("project_id", job.ProjectID).Msg("interrupted job abandoned")
+			continue
+		}
+
+		job.Status = domain.JobStatusPending
+		if err := uc.jobRepo.Update(ctx, job); err != nil {
+			return err
+		}
+		uc.updateProjectStatus(ctx, job.ProjectID, domain.ProjectStatusQueued)
+		log.Info().Uint("job_id", job.ID).Uint("project_id", job.ProjectID).Msg("interrupted job requeued")
+	}
+
+	stuck, err := uc.projectRepo.GetByStatus(ctx, domain.InProgressStatuses()...)
+	if err != nil {
+		return err
+	}
+	for _, project := range stuck {
+		job, err := uc.jobRepo.GetLatestByProjectID(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+		if job != nil && job.Status == domain.JobStatusPending {
+			continue
+		}
+		if err := uc.enqueue(ctx, project); err != nil {
+			return err
+		}
+		uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusQueued)
+		log.Info().Uint("project_id", project.ID).Msg("stuck project reclaimed")
+	}
+
+	return nil
+}
+
+// abandonProject marca com erro o projeto de um job abandonado, registrando em
+// FailedStep a etapa que executava quando o job foi interrompido, para que
+// RetryProject retome a partir dela
+func (uc *ProjectUseCase) abandonProject(ctx context.Context, projectID uint, reason string) {
+	project, err := uc.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		log.Error().Err(err).Uint("project_id", projectID).Msg("failed to load project for status update")
+		return
+	}
+	if step := interruptedStep(project); step != nil {
+		now := time.Now()
+		project.FailedStep = step.Name
+		step.Status = domain.StepStatusFailed
+		step.Error = reason
+		step.FinishedAt = &now
+		if err := uc.stepRepo.Update(ctx, step); err != nil {
+			log.Error().Err(err).Uint("project_id", projectID).Str("step", step.Name).Msg("failed to record step result")
+		}
+	}
+	if err := uc.setStatus(ctx, project, domain.ProjectStatusError); err != nil {
+		log.Error().Err(err).Uint("project_id", projectID).Msg("failed to update project status")
+	}
+}
+
+// interruptedStep retorna a primeira etapa não concluída do projeto: a que
+// estava em execução ou, entre duas etapas, a próxima a executar
+func interruptedStep(project *domain.Project) *domain.ProjectStep {
+	for i := range project.Steps {
+		switch project.Steps[i].Status {
+		case domain.StepStatusDone, domain.StepStatusSkipped:
+			continue
+		}
+		return &project.Steps[i]
+	}
+	return nil
+}
+
+// runWorker consome a fila até ctx ser cancelado
+func (uc *ProjectUseCase) runWorker(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, jobCtx, err := uc.claimJob(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to claim job")
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-uc.wake:
+			case <-time.After(jobPollInterval):
+			}
+			continue
+		}
+
+		uc.announceQueuePositions(ctx)
+		uc.runJob(ctx, jobCtx, job)
+		uc.releaseJob(job)
+	}
+}
+
+// runJob executa um job reivindicado em jobCtx e grava o resultado
+func (uc *ProjectUseCase) runJob(ctx, jobCtx context.Context, job *domain.ProjectJob) {
+	log.Info().Uint("job_id", job.ID).Uint("project_id", job.ProjectID).Int("attempt", job.Attempts).Msg("job started")
+
+	err := uc.executeJob(jobCtx, job)
+
+	now := time.Now()
+	job.FinishedAt = &now
+	if err != nil && errors.Is(context.Cause(jobCtx), ErrProjectCancelled) {
+		job.Status = domain.JobStatusCancelled
+		job.LastError = err.Error()
+		log.Info().Uint("job_id", job.ID).Msg("job cancelled")
+	} else if err != nil {
+		job.Status = domain.JobStatusFailed
+		job.LastError = err.Error()
+		log.Error().Err(err).Uint("job_id", job.ID).Msg("job failed")
+	} else {
+		job.Status = domain.JobStatusDone
+		job.LastError = ""
+		log.Info().Uint("job_id", job.ID).Msg("job finished")
+	}
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		log.Error().Err(err).Uint("job_id", job.ID).Msg("failed to record job result")
+	}
+}
+
+// executeJob carrega o projeto do job e executa a criação
+func (uc *ProjectUseCase) executeJob(ctx context.Context, job *domain.ProjectJob) error {
+	project, err := uc.projectRepo.GetByID(ctx, job.ProjectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return errors.New("project not found")
+	}
+	return uc.processProjectCreation(ctx, project, &project.Template)
+}
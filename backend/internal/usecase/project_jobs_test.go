@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/localgit"
+	"testing"
+)
+
+func TestClaimOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		pending []string
+		running map[string]int
+		limits  map[string]int
+		want    []int
+	}{
+		{
+			name:    "without limits keeps arrival order",
+			pending: []string{"github", "gitlab", "github"},
+			want:    []int{1, 2, 3},
+		},
+		{
+			name:    "saturated provider waits behind newer jobs",
+			pending: []string{"gitlab", "gitlab", "github", "gitlab", "github"},
+			running: map[string]int{"gitlab": 1},
+			limits:  map[string]int{"gitlab": 1},
+			want:    []int{3, 5, 1, 2, 4},
+		},
+		{
+			name:    "claimed jobs fill the remaining slots",
+			pending: []string{"gitlab", "gitlab", "github", "gitlab"},
+			running: map[string]int{"gitlab": 1},
+			limits:  map[string]int{"gitlab": 2, "github": 1},
+			want:    []int{1, 3, 2, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pending []*domain.ProjectJob
+			for i, provider := range tt.pending {
+				pending = append(pending, &domain.ProjectJob{ID: uint(i + 1), ProjectID: uint(i + 1), Provider: provider})
+			}
+			running := map[string]int{}
+			for provider, count := range tt.running {
+				running[provider] = count
+			}
+
+			var got []int
+			for _, job := range claimOrder(pending, running, tt.limits) {
+				got = append(got, int(job.ID))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("claim order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelPendingJobAnnouncesQueuePositions(t *testing.T) {
+	f := newCreationFixture(t)
+	ctx := context.Background()
+	template := f.createProject(t, domain.RollbackPolicyDelete).Template
+
+	// Sem workers iniciados, os jobs permanecem na fila
+	var queued []*domain.Project
+	for i := 1; i <= 3; i++ {
+		project := &domain.Project{
+			Name:       fmt.Sprintf("queued-%d", i),
+			TemplateID: template.ID,
+			Status:     domain.ProjectStatusQueued,
+			Provider:   localgit.ProviderName,
+		}
+		if err := f.uc.projectRepo.Create(ctx, project); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.uc.enqueue(ctx, project); err != nil {
+			t.Fatal(err)
+		}
+		queued = append(queued, project)
+	}
+
+	if err := f.uc.CancelProject(ctx, queued[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, project := range queued[1:] {
+		logs := f.uc.logs.GetLogs(project.ID)
+		last := logs[len(logs)-1].Message
+		if want := fmt.Sprintf("Queue position: %d", i+1); last != want {
+			t.Errorf("%s last log = %q, want %q", project.Name, last, want)
+		}
+	}
+	positions, err := f.uc.queuePositions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := positions[queued[0].ID]; ok || len(positions) != 2 {
+		t.Errorf("positions = %v, want only the two remaining projects", positions)
+	}
+}
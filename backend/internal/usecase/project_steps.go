@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"template-manager-backend/internal/domain"
+	"time"
+
+	"github.com/phuslu/log"
+)
+
+// setStatus aplica uma transição da máquina de estados ao projeto e grava o
+// projeto. Transições não previstas em domain.CanTransition são recusadas.
+func (uc *ProjectUseCase) setStatus(ctx context.Context, project *domain.Project, status string) error {
+	if !domain.CanTransition(project.Status, status) {
+		return fmt.Errorf("invalid status transition from %s to %s", project.Status, status)
+	}
+
+	previous := project.Status
+	changed := previous != status
+	project.Status = status
+	if err := uc.projectRepo.Update(ctx, project); err != nil {
+		return err
+	}
+	if changed {
+		log.Info().Uint("project_id", project.ID).Str("status", status).Msg("status updated")
+		uc.logs.Emit(project.ID, domain.ProjectLog{
+			Type:    domain.LogEventStatus,
+			Message: "Status changed to " + status,
+			Status:  status,
+		})
+		uc.events.Publish(domain.EventProjectStatusChanged, project.ID, domain.ProjectStatusChange{
+			Name:           project.Name,
+			Status:         status,
+			PreviousStatus: previous,
+			FailedStep:     project.FailedStep,
+		})
+	}
+	return nil
+}
+
+// resetSteps substitui as etapas da tentativa anterior por etapas pendentes
+func (uc *ProjectUseCase) resetSteps(ctx context.Context, project *domain.Project) error {
+	names := domain.ProjectSteps()
+	steps := make([]domain.ProjectStep, 0, len(names))
+	for _, name := range names {
+		steps = append(steps, domain.ProjectStep{
+			ProjectID: project.ID,
+			Name:      name,
+			Status:    domain.StepStatusPending,
+		})
+	}
+	project.Steps = steps
+	return uc.stepRepo.Replace(ctx, project.ID, project.Steps)
+}
+
+// beginStep marca a etapa como em execução e avança o status do projeto. Os
+// eventos de log seguintes são atribuídos à etapa até endStep.
+func (uc *ProjectUseCase) beginStep(ctx context.Context, project *domain.Project, name string) {
+	if err := uc.setStatus(ctx, project, domain.StepStatus(name)); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Str("step", name).Msg("failed to update project status")
+	}
+	uc.logs.SetStep(project.ID, name)
+	uc.logs.Emit(project.ID, domain.ProjectLog{
+		Type:    domain.LogEventStep,
+		Step:    name,
+		Message: "Step " + name + " started",
+		Status:  domain.StepStatusRunning,
+	})
+
+	step := findStep(project, name)
+	if step == nil {
+		return
+	}
+	now := time.Now()
+	step.Status = domain.StepStatusRunning
+	step.StartedAt = &now
+	if err := uc.stepRepo.Update(ctx, step); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Str("step", name).Msg("failed to record step start")
+	}
+}
+
+// endStep grava o resultado da etapa e a mensagem de erro, quando houver, e
+// publica no log o resultado com a duração da etapa
+func (uc *ProjectUseCase) endStep(ctx context.Context, project *domain.Project, name, status, message string) {
+	uc.logs.SetStep(project.ID, "")
+	step := findStep(project, name)
+	if step == nil {
+		return
+	}
+	now := time.Now()
+	step.Status = status
+	step.Error = message
+	step.FinishedAt = &now
+	if err := uc.stepRepo.Update(ctx, step); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Str("step", name).Msg("failed to record step result")
+	}
+
+	event := domain.ProjectLog{
+		Type:    domain.LogEventStep,
+		Step:    name,
+		Message: "Step " + name + " " + status,
+		Error:   message,
+		Status:  status,
+	}
+	switch status {
+	case domain.StepStatusFailed:
+		event.Level = domain.LogLevelError
+	case domain.StepStatusCancelled:
+		event.Level = domain.LogLevelWarn
+	}
+	if step.StartedAt != nil {
+		event.DurationMs = now.Sub(*step.StartedAt).Milliseconds()
+	}
+	uc.logs.Emit(project.ID, event)
+}
+
+// findStep retorna a etapa da tentativa atual com o nome informado
+func findStep(project *domain.Project, name string) *domain.ProjectStep {
+	for i := range project.Steps {
+		if project.Steps[i].Name == name {
+			return &project.Steps[i]
+		}
+	}
+	return nil
+}
@@ -6,30 +6,69 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/gitcli"
+	"template-manager-backend/pkg/manifest"
+	"template-manager-backend/pkg/render"
+	"time"
 
 	"github.com/phuslu/log"
 )
 
+const (
+	// readyStatusAttempts limita as tentativas de gravar o status "ready"
+	readyStatusAttempts = 3
+	// readyStatusRetryDelay é a espera base entre essas tentativas
+	readyStatusRetryDelay = 200 * time.Millisecond
+)
+
 // ProjectUseCase implementa a lógica de negócio para projetos
 type ProjectUseCase struct {
 	projectRepo  domain.ProjectRepository
 	templateRepo domain.TemplateRepository
-	gitService   domain.GitService
+	jobRepo      domain.JobRepository
+	stepRepo     domain.ProjectStepRepository
+	auditRepo    domain.AuditRepository
+	providers    domain.GitProviders
 	logs         *LogManager
+	events       *EventBus
+	// wake acorda um worker ocioso quando um job é enfileirado ou uma vaga é liberada
+	wake chan struct{}
+
+	queue     QueueConfig
+	queueMu   sync.Mutex
+	running   map[string]int
+	positions map[uint]int
+	// cancels guarda o cancelamento do contexto de cada job em execução
+	cancels map[uint]context.CancelCauseFunc
 }
 
-// NewProjectUseCase cria uma nova instância do use case de projetos
+// NewProjectUseCase cria uma nova instância do use case de projetos.
+// Os jobs de criação só são processados após a chamada de Start.
 func NewProjectUseCase(
 	projectRepo domain.ProjectRepository,
 	templateRepo domain.TemplateRepository,
-	gitService domain.GitService,
+	jobRepo domain.JobRepository,
+	stepRepo domain.ProjectStepRepository,
+	auditRepo domain.AuditRepository,
+	logs *LogManager,
+	events *EventBus,
+	providers domain.GitProviders,
 ) *ProjectUseCase {
 	return &ProjectUseCase{
 		projectRepo:  projectRepo,
 		templateRepo: templateRepo,
-		gitService:   gitService,
-		logs:         NewLogManager(),
+		jobRepo:      jobRepo,
+		stepRepo:     stepRepo,
+		auditRepo:    auditRepo,
+		providers:    providers,
+		logs:         logs,
+		events:       events,
+		wake:         make(chan struct{}, 1),
+		running:      make(map[string]int),
+		positions:    make(map[uint]int),
+		cancels:      make(map[uint]context.CancelCauseFunc),
 	}
 }
 
@@ -54,11 +93,40 @@ func (uc *ProjectUseCase) CreateProject(ctx context.Context, req *domain.CreateP
 		return nil, errors.New("template not found")
 	}
 
-	// Criar o projeto com status "creating"
+	// Validar as variáveis contra o manifesto do template
+	variables, err := manifest.Resolve(template.Manifest, req.Variables)
+	if err != nil {
+		log.Warn().Err(err).Uint("template_id", req.TemplateID).Msg("invalid template variables")
+		return nil, err
+	}
+
+	// Verificar se o provedor de destino está configurado
+	provider := req.Provider
+	if provider == "" {
+		provider = uc.providers.Default()
+	}
+	if _, err := uc.providers.Get(provider); err != nil {
+		log.Warn().Str("provider", provider).Msg("git provider not configured")
+		return nil, err
+	}
+
+	ref := template.Ref
+	if req.TemplateRef != "" {
+		if err := gitcli.ValidateRef(req.TemplateRef); err != nil {
+			log.Warn().Str("template_ref", req.TemplateRef).Msg("invalid template ref")
+			return nil, err
+		}
+		ref = req.TemplateRef
+	}
+
+	// Criar o projeto com status "queued"
 	project := &domain.Project{
-		Name:       req.Name,
-		TemplateID: req.TemplateID,
-		Status:     domain.ProjectStatusCreating,
+		Name:        req.Name,
+		TemplateID:  req.TemplateID,
+		Status:      domain.ProjectStatusQueued,
+		Provider:    provider,
+		TemplateRef: ref,
+		Variables:   variables,
 	}
 
 	if err := uc.projectRepo.Create(ctx, project); err != nil {
@@ -68,88 +136,389 @@ func (uc *ProjectUseCase) CreateProject(ctx context.Context, req *domain.CreateP
 
 	log.Info().Uint("project_id", project.ID).Msg("project record created")
 
-	// Processar a criação do projeto em background
-	go uc.processProjectCreation(context.Background(), project, template)
+	// Enfileirar a criação do projeto para os workers
+	if err := uc.enqueue(ctx, project); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to enqueue project creation")
+		uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusError)
+		uc.logs.Close(project.ID)
+		return nil, err
+	}
+	uc.events.Publish(domain.EventProjectQueued, project.ID, project)
 
 	return project, nil
 }
 
-// processProjectCreation processa a criação do projeto em background
-func (uc *ProjectUseCase) processProjectCreation(ctx context.Context, project *domain.Project, template *domain.Template) {
+// processProjectCreation executa as etapas de criação do projeto, avançando o
+// status pela máquina de estados e registrando cada etapa. Em caso de falha o
+// projeto é marcado com erro, a etapa é registrada em FailedStep e o erro da
+// etapa é retornado. As etapas locais são sempre refeitas, pois o diretório
+// temporário não sobrevive à tentativa anterior; o repositório remoto já
+// criado é reaproveitado.
+func (uc *ProjectUseCase) processProjectCreation(ctx context.Context, project *domain.Project, template *domain.Template) error {
 	log.Info().Uint("project_id", project.ID).Msg("starting project creation")
-	uc.logs.Append(project.ID, "Starting project creation")
+	if project.FailedStep != "" {
+		uc.logs.Append(project.ID, "Resuming project creation from step "+project.FailedStep)
+	} else {
+		uc.logs.Append(project.ID, "Starting project creation")
+	}
 	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("template-%d", project.ID))
 	defer os.RemoveAll(tempDir)
+	// Remover sobras de uma execução interrompida
+	os.RemoveAll(tempDir)
+
+	if err := uc.resetSteps(ctx, project); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to record project steps")
+	}
+	// A saída dos comandos git entra no log, atribuída à etapa em execução
+	ctx = domain.WithOutput(ctx, func(line string) {
+		uc.logs.Append(project.ID, line)
+	})
 
 	// 1. Clonar o repositório template
+	uc.beginStep(ctx, project, domain.ProjectStepClone)
+	gitService, err := uc.providers.Get(project.Provider)
+	if err != nil {
+		return uc.failCreation(ctx, project, domain.ProjectStepClone, "Git provider not available: "+project.Provider, err)
+	}
+
 	uc.logs.Append(project.ID, "Cloning template repository")
-	if err := uc.gitService.CloneRepository(ctx, template.GitURL, tempDir); err != nil {
-		log.Error().Err(err).Msg("failed to clone repository")
-		uc.logs.Append(project.ID, "Failed to clone repository")
-		uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusError)
-		uc.logs.Close(project.ID)
-		return
+	source := domain.CloneSource{
+		GitURL:       template.GitURL,
+		Ref:          project.TemplateRef,
+		Subdirectory: template.Subdirectory,
+	}
+	if project.TemplateCommit != "" {
+		// Uma nova tentativa, ou um job retomado após um reinício, gera o mesmo
+		// conteúdo da anterior mesmo que o ref do template tenha avançado
+		source.Ref = project.TemplateCommit
+	}
+	commit, err := gitService.CloneRepository(ctx, source, tempDir)
+	if err != nil {
+		return uc.failCreation(ctx, project, domain.ProjectStepClone, "Failed to clone repository", err)
+	}
+	log.Info().Str("commit", commit).Msg("repository cloned")
+	uc.logs.Append(project.ID, "Repository cloned at commit "+commit)
+	project.TemplateCommit = commit
+	if err := uc.projectRepo.Update(ctx, project); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to record template commit")
 	}
-	log.Info().Msg("repository cloned")
-	uc.logs.Append(project.ID, "Repository cloned")
+
+	// Em monorepos apenas a pasta do template compõe o projeto gerado
+	workDir := filepath.Join(tempDir, template.Subdirectory)
+	if info, err := os.Stat(workDir); err != nil || !info.IsDir() {
+		return uc.failCreation(ctx, project, domain.ProjectStepClone, "Template subdirectory not found: "+template.Subdirectory, err)
+	}
+	uc.endStep(ctx, project, domain.ProjectStepClone, domain.StepStatusDone, "")
 
 	// 2. Limpar histórico de commits
+	uc.beginStep(ctx, project, domain.ProjectStepClearHistory)
 	uc.logs.Append(project.ID, "Clearing git history")
-	if err := uc.gitService.ClearGitHistory(ctx, tempDir); err != nil {
-		log.Error().Err(err).Msg("failed to clear git history")
-		uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusError)
-		uc.logs.Append(project.ID, "Failed to clear git history")
-		uc.logs.Close(project.ID)
-		return
+	if err := gitService.ClearGitHistory(ctx, tempDir); err != nil {
+		return uc.failCreation(ctx, project, domain.ProjectStepClearHistory, "Failed to clear git history", err)
 	}
 	log.Info().Msg("git history cleared")
 	uc.logs.Append(project.ID, "Git history cleared")
+	uc.endStep(ctx, project, domain.ProjectStepClearHistory, domain.StepStatusDone, "")
 
-	// 3. Criar novo repositório no GitHub
-	uc.logs.Append(project.ID, "Creating repository on GitHub")
-	repoURL, err := uc.gitService.CreateRepository(ctx, project.Name, fmt.Sprintf("Project created from template: %s", template.Name))
+	// 3. Renderizar as variáveis do template
+	uc.beginStep(ctx, project, domain.ProjectStepRender)
+	uc.logs.Append(project.ID, "Rendering template variables")
+	// O manifesto descreve o template e não faz parte do projeto gerado
+	os.Remove(filepath.Join(workDir, manifest.FileName))
+	vars := templateVariables(project, template)
+	removed, err := manifest.ApplyRules(workDir, template.Manifest, vars)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to create repository on github")
-		uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusError)
-		uc.logs.Append(project.ID, "Failed to create repository on GitHub")
-		uc.logs.Close(project.ID)
-		return
+		return uc.failCreation(ctx, project, domain.ProjectStepRender, "Failed to apply template rules: "+err.Error(), err)
+	}
+	if len(removed) > 0 {
+		uc.logs.Append(project.ID, fmt.Sprintf("Template rules excluded %d files", len(removed)))
 	}
-	log.Info().Str("repo_url", repoURL).Msg("repository created")
-	uc.logs.Append(project.ID, "Repository created")
+	if err := render.Directory(workDir, vars); err != nil {
+		return uc.failCreation(ctx, project, domain.ProjectStepRender, "Failed to render template variables", err)
+	}
+	if err := render.Paths(workDir, vars); err != nil {
+		return uc.failCreation(ctx, project, domain.ProjectStepRender, "Failed to render file paths: "+err.Error(), err)
+	}
+	log.Info().Msg("template variables rendered")
+	uc.logs.Append(project.ID, "Template variables rendered")
+	uc.endStep(ctx, project, domain.ProjectStepRender, domain.StepStatusDone, "")
 
-	// 4. Fazer push para o novo repositório
+	// 4. Criar novo repositório no provedor, reaproveitando o de uma tentativa anterior
+	repoURL := project.GitURL
+	if repoURL != "" {
+		uc.logs.Append(project.ID, "Reusing repository "+repoURL)
+		uc.endStep(ctx, project, domain.ProjectStepCreateRepository, domain.StepStatusSkipped, "")
+	} else {
+		uc.beginStep(ctx, project, domain.ProjectStepCreateRepository)
+		uc.logs.Append(project.ID, "Creating repository on "+project.Provider)
+		repoURL, err = gitService.CreateRepository(ctx, project.Name, fmt.Sprintf("Project created from template: %s", template.Name))
+		if err != nil {
+			return uc.failCreation(ctx, project, domain.ProjectStepCreateRepository, "Failed to create repository on "+project.Provider, err)
+		}
+		log.Info().Str("repo_url", repoURL).Msg("repository created")
+		uc.logs.Append(project.ID, "Repository created")
+		// Registrar a URL logo após a criação para que o repositório possa ser
+		// removido se a geração for cancelada ou reaproveitado numa nova tentativa
+		project.GitURL = repoURL
+		if err := uc.projectRepo.Update(ctx, project); err != nil {
+			log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to record repository url")
+		}
+		uc.endStep(ctx, project, domain.ProjectStepCreateRepository, domain.StepStatusDone, "")
+	}
+
+	// 5. Fazer push para o novo repositório
+	uc.beginStep(ctx, project, domain.ProjectStepPush)
 	uc.logs.Append(project.ID, "Pushing code to repository")
-	if err := uc.gitService.PushToRepository(ctx, tempDir, repoURL); err != nil {
-		log.Error().Err(err).Msg("failed to push project")
-		uc.updateProjectStatus(ctx, project.ID, domain.ProjectStatusError)
-		uc.logs.Append(project.ID, "Failed to push code")
-		uc.logs.Close(project.ID)
-		return
+	if err := gitService.PushToRepository(ctx, workDir, repoURL); err != nil {
+		return uc.failCreation(ctx, project, domain.ProjectStepPush, "Failed to push code", err)
 	}
 	log.Info().Msg("code pushed to repository")
 	uc.logs.Append(project.ID, "Code pushed to repository")
+	uc.endStep(ctx, project, domain.ProjectStepPush, domain.StepStatusDone, "")
 
-	// 5. Atualizar o projeto com a URL do repositório e status "ready"
+	// 6. Atualizar o projeto com a URL do repositório e status "ready"
 	project.GitURL = repoURL
-	project.Status = domain.ProjectStatusReady
-	uc.projectRepo.Update(ctx, project)
+	project.FailedStep = ""
+	// O status "ready" é gravado sob queueMu, o mesmo lock de CancelProject:
+	// um cancelamento que chegou durante o push ainda desfaz o repositório e
+	// um posterior encontra o projeto já pronto
+	uc.queueMu.Lock()
+	if errors.Is(context.Cause(ctx), ErrProjectCancelled) {
+		uc.queueMu.Unlock()
+		return uc.failCreation(ctx, project, domain.ProjectStepPush, "Project creation cancelled", nil)
+	}
+	err = uc.markReady(ctx, project)
+	uc.queueMu.Unlock()
+	if err != nil {
+		// Com o push concluído o repositório está completo e nunca é desfeito.
+		// O projeto continua em "pushing" e é retomado na próxima
+		// inicialização, reaproveitando o repositório.
+		log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to mark project as ready")
+		uc.logs.Emit(project.ID, domain.ProjectLog{
+			Level:   domain.LogLevelError,
+			Message: "Failed to mark project as ready",
+			Error:   err.Error(),
+		})
+		uc.logs.Close(project.ID)
+		return fmt.Errorf("failed to mark project as ready: %w", err)
+	}
 	log.Info().Uint("project_id", project.ID).Msg("project ready")
 	uc.logs.Append(project.ID, "Project ready")
 	uc.logs.Close(project.ID)
+	return nil
+}
+
+// markReady grava o status "ready" depois do push, tentando de novo quando a
+// gravação falha. O contexto do job é desconsiderado: com o push concluído,
+// nem o desligamento do worker deve impedir o registro.
+func (uc *ProjectUseCase) markReady(ctx context.Context, project *domain.Project) error {
+	ctx = context.WithoutCancel(ctx)
+	previous := project.Status
+	for attempt := 1; ; attempt++ {
+		err := uc.setStatus(ctx, project, domain.ProjectStatusReady)
+		if err == nil {
+			return nil
+		}
+		// setStatus altera o status em memória antes de gravar
+		project.Status = previous
+		if attempt == readyStatusAttempts {
+			return err
+		}
+		log.Warn().Err(err).Uint("project_id", project.ID).Int("attempt", attempt).Msg("failed to mark project as ready, retrying")
+		time.Sleep(time.Duration(attempt) * readyStatusRetryDelay)
+	}
+}
+
+// failCreation registra a falha de uma etapa, marca o projeto com erro,
+// encerra o log e retorna o erro a ser gravado no job. Quando a falha vem de
+// CancelProject, o projeto é cancelado. Nos dois casos o repositório remoto é
+// tratado conforme a política de rollback do template.
+func (uc *ProjectUseCase) failCreation(ctx context.Context, project *domain.Project, step, message string, err error) error {
+	if err == nil {
+		err = errors.New(message)
+	} else {
+		err = fmt.Errorf("%s: %w", message, err)
+	}
+
+	cancelled := errors.Is(context.Cause(ctx), ErrProjectCancelled)
+	// O contexto do job pode já estar cancelado; as atualizações finais não devem falhar por isso
+	ctx = context.WithoutCancel(ctx)
+	project.FailedStep = step
+
+	if cancelled {
+		log.Info().Uint("project_id", project.ID).Msg("project creation cancelled")
+		uc.endStep(ctx, project, step, domain.StepStatusCancelled, err.Error())
+		uc.rollbackRemote(ctx, project, project.Template.RollbackPolicy)
+		uc.logs.Log(project.ID, domain.LogLevelWarn, "Project creation cancelled")
+		if err := uc.setStatus(ctx, project, domain.ProjectStatusCancelled); err != nil {
+			log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to update project status")
+		}
+		uc.logs.Close(project.ID)
+		return err
+	}
+
+	log.Error().Err(err).Uint("project_id", project.ID).Msg("project creation failed")
+	uc.logs.Emit(project.ID, domain.ProjectLog{
+		Level:   domain.LogLevelError,
+		Step:    step,
+		Message: message,
+		Error:   err.Error(),
+	})
+	uc.endStep(ctx, project, step, domain.StepStatusFailed, err.Error())
+	// Desfazer a criação do repositório remoto conforme a política do template
+	uc.rollbackRemote(ctx, project, project.Template.RollbackPolicy)
+	if err := uc.setStatus(ctx, project, domain.ProjectStatusError); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to update project status")
+	}
+	uc.logs.Close(project.ID)
+	return err
+}
+
+// rollbackRemote desfaz a criação do repositório remoto do projeto, se houver,
+// removendo ou arquivando conforme a política. Com a política "keep" o
+// repositório é mantido e reaproveitado numa nova tentativa.
+func (uc *ProjectUseCase) rollbackRemote(ctx context.Context, project *domain.Project, policy string) {
+	if project.GitURL == "" || policy == domain.RollbackPolicyKeep {
+		return
+	}
+
+	action := "deleted"
+	if policy == domain.RollbackPolicyArchive {
+		action = "archived"
+		uc.logs.Append(project.ID, "Archiving repository "+project.GitURL)
+	} else {
+		uc.logs.Append(project.ID, "Deleting repository "+project.GitURL)
+	}
+	err := uc.removeRemote(ctx, project, policy)
+	uc.audit(ctx, domain.AuditActionProjectRollback, project, policy, err)
+	if err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Str("repo_url", project.GitURL).Msg("failed to roll back remote repository")
+		uc.logs.Emit(project.ID, domain.ProjectLog{
+			Level:   domain.LogLevelError,
+			Message: "Failed to roll back repository",
+			Error:   err.Error(),
+		})
+		return
+	}
+	log.Info().Uint("project_id", project.ID).Str("repo_url", project.GitURL).Str("action", action).Msg("remote repository rolled back")
+	uc.logs.Append(project.ID, "Repository "+action)
+
+	project.GitURL = ""
+	if err := uc.projectRepo.Update(ctx, project); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Msg("failed to clear repository url")
+	}
+}
+
+// removeRemote remove ou arquiva o repositório remoto do projeto no provedor
+func (uc *ProjectUseCase) removeRemote(ctx context.Context, project *domain.Project, policy string) error {
+	gitService, err := uc.providers.Get(project.Provider)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if policy == domain.RollbackPolicyArchive {
+		return gitService.ArchiveRepository(ctx, project.GitURL)
+	}
+	return gitService.DeleteRepository(ctx, project.GitURL)
+}
+
+// audit registra na auditoria uma operação sobre o repositório remoto do projeto
+func (uc *ProjectUseCase) audit(ctx context.Context, action string, project *domain.Project, remote string, err error) {
+	entry := &domain.AuditEntry{
+		Action:      action,
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		RepoURL:     project.GitURL,
+		Remote:      remote,
+		Result:      domain.AuditResultSucceeded,
+	}
+	if err != nil {
+		entry.Result = domain.AuditResultFailed
+		entry.Error = err.Error()
+	}
+	if err := uc.auditRepo.Create(ctx, entry); err != nil {
+		log.Error().Err(err).Uint("project_id", project.ID).Str("action", action).Msg("failed to record audit entry")
+	}
 }
 
-// updateProjectStatus atualiza apenas o status do projeto
+// templateVariables monta o conjunto de variáveis disponível para renderização.
+// As variáveis embutidas têm precedência sobre os valores informados na requisição.
+func templateVariables(project *domain.Project, template *domain.Template) map[string]any {
+	vars := make(map[string]any, len(project.Variables)+2)
+	for key, value := range project.Variables {
+		vars[key] = value
+	}
+	vars[manifest.ProjectNameVariable] = project.Name
+	vars[manifest.TemplateNameVariable] = template.Name
+	return vars
+}
+
+// updateProjectStatus carrega o projeto e aplica a transição de status
 func (uc *ProjectUseCase) updateProjectStatus(ctx context.Context, projectID uint, status string) {
 	project, err := uc.projectRepo.GetByID(ctx, projectID)
-	if err != nil {
+	if err != nil || project == nil {
 		log.Error().Err(err).Uint("project_id", projectID).Msg("failed to load project for status update")
 		return
 	}
-	project.Status = status
-	uc.projectRepo.Update(ctx, project)
-	log.Info().Uint("project_id", projectID).Str("status", status).Msg("status updated")
-	uc.logs.Append(projectID, "status: "+status)
+	if err := uc.setStatus(ctx, project, status); err != nil {
+		log.Error().Err(err).Uint("project_id", projectID).Msg("failed to update project status")
+	}
+}
+
+// RetryProject coloca de volta na fila um projeto com erro ou cancelado. A
+// criação retoma a partir da etapa que falhou.
+func (uc *ProjectUseCase) RetryProject(ctx context.Context, id uint) (*domain.Project, error) {
+	project, err := uc.queueRetry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.enqueue(ctx, project); err != nil {
+		log.Error().Err(err).Uint("project_id", id).Msg("failed to enqueue project retry")
+		uc.updateProjectStatus(ctx, id, domain.ProjectStatusError)
+		uc.logs.Close(id)
+		return nil, err
+	}
+	return project, nil
+}
+
+// queueRetry marca o projeto como "queued" para uma nova tentativa. A
+// verificação acontece sob queueMu, o lock de claimJob e releaseJob, para que
+// duas tentativas simultâneas não enfileirem dois jobs e para que o job
+// anterior, que grava o erro antes de terminar, já tenha sido liberado.
+func (uc *ProjectUseCase) queueRetry(ctx context.Context, id uint) (*domain.Project, error) {
+	uc.queueMu.Lock()
+	defer uc.queueMu.Unlock()
+
+	project, err := uc.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, errors.New("project not found")
+	}
+	if project.Status != domain.ProjectStatusError && project.Status != domain.ProjectStatusCancelled {
+		return nil, errors.New("only failed or cancelled projects can be retried")
+	}
+	if _, running := uc.cancels[id]; running {
+		return nil, errors.New("project creation is still running")
+	}
+	job, err := uc.jobRepo.GetLatestByProjectID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job != nil && (job.Status == domain.JobStatusPending || job.Status == domain.JobStatusRunning) {
+		return nil, errors.New("project creation is still running")
+	}
+
+	log.Info().Uint("project_id", id).Str("failed_step", project.FailedStep).Msg("retrying project creation")
+	uc.logs.Reopen(id)
+	if err := uc.setStatus(ctx, project, domain.ProjectStatusQueued); err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
 // GetProject busca um projeto por ID
@@ -161,16 +530,43 @@ func (uc *ProjectUseCase) GetProject(ctx context.Context, id uint) (*domain.Proj
 	if project == nil {
 		return nil, errors.New("project not found")
 	}
+
+	job, err := uc.jobRepo.GetLatestByProjectID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	project.Job = job
+
+	positions, err := uc.queuePositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	project.QueuePosition = positions[project.ID]
 	return project, nil
 }
 
 // GetAllProjects busca todos os projetos
 func (uc *ProjectUseCase) GetAllProjects(ctx context.Context) ([]*domain.Project, error) {
-	return uc.projectRepo.GetAll(ctx)
+	projects, err := uc.projectRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := uc.queuePositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		project.QueuePosition = positions[project.ID]
+	}
+	return projects, nil
 }
 
-// DeleteProject remove um projeto
-func (uc *ProjectUseCase) DeleteProject(ctx context.Context, id uint) error {
+// DeleteProject remove um projeto. remote define o destino do repositório
+// remoto (delete, archive ou keep; vazio mantém). A remoção do repositório é
+// destrutiva e exige que confirm seja o nome do projeto. Toda remoção é
+// registrada na auditoria com o destino dado ao repositório remoto.
+func (uc *ProjectUseCase) DeleteProject(ctx context.Context, id uint, remote, confirm string) error {
 	project, err := uc.projectRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -179,15 +575,67 @@ func (uc *ProjectUseCase) DeleteProject(ctx context.Context, id uint) error {
 		return errors.New("project not found")
 	}
 
-	return uc.projectRepo.Delete(ctx, id)
+	if remote == "" {
+		remote = domain.RollbackPolicyKeep
+	}
+	if !domain.IsValidRollbackPolicy(remote) {
+		return errors.New("remote must be delete, archive or keep")
+	}
+	if remote == domain.RollbackPolicyDelete && confirm != project.Name {
+		return errors.New("deleting the remote repository requires confirm to match the project name")
+	}
+	if domain.IsInProgress(project.Status) {
+		return errors.New("project is being created; cancel it before deleting")
+	}
+
+	removesRemote := remote != domain.RollbackPolicyKeep && project.GitURL != ""
+	if removesRemote {
+		err := uc.removeRemote(ctx, project, remote)
+		uc.audit(ctx, domain.AuditActionProjectDelete, project, remote, err)
+		if err != nil {
+			log.Error().Err(err).Uint("project_id", id).Str("remote", remote).Msg("failed to remove remote repository")
+			// O projeto é mantido para que a remoção possa ser repetida
+			return fmt.Errorf("failed to %s remote repository: %w", remote, err)
+		}
+		log.Info().Uint("project_id", id).Str("remote", remote).Str("repo_url", project.GitURL).Msg("remote repository removed")
+	}
+
+	if err := uc.jobRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.stepRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.logs.Delete(id); err != nil {
+		return err
+	}
+	if err := uc.projectRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if !removesRemote {
+		// O repositório remoto foi mantido ou o projeto não chegou a ter um
+		if project.GitURL == "" {
+			remote = domain.AuditRemoteNone
+		}
+		uc.audit(ctx, domain.AuditActionProjectDelete, project, remote, nil)
+	}
+	uc.events.Publish(domain.EventProjectDeleted, id, nil)
+	return nil
+}
+
+// ListProviders retorna os provedores de hospedagem configurados e o padrão
+func (uc *ProjectUseCase) ListProviders() ([]string, string) {
+	return uc.providers.Names(), uc.providers.Default()
 }
 
-// SubscribeLogs retorna um canal para receber os logs do projeto.
-func (uc *ProjectUseCase) SubscribeLogs(projectID uint) <-chan string {
-	return uc.logs.Subscribe(projectID)
+// SubscribeLogs retorna uma inscrição que entrega, em ordem, os eventos de
+// log do projeto com sequência maior que after. A inscrição deve ser
+// encerrada com Close.
+func (uc *ProjectUseCase) SubscribeLogs(projectID uint, after int) *LogSubscription {
+	return uc.logs.Subscribe(projectID, after)
 }
 
-// GetLogs retorna todos os logs registrados para o projeto.
-func (uc *ProjectUseCase) GetLogs(projectID uint) []string {
+// GetLogs retorna todos os eventos de log registrados para o projeto.
+func (uc *ProjectUseCase) GetLogs(projectID uint) []domain.ProjectLog {
 	return uc.logs.GetLogs(projectID)
 }
@@ -3,18 +3,34 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/manifest"
+	"time"
+
+	"github.com/phuslu/log"
 )
 
+// manifestCloneTimeout limita o clone feito durante a requisição para ler o
+// manifesto, para que um remoto travado não prenda a requisição
+const manifestCloneTimeout = time.Minute
+
 // TemplateUseCase implementa a lógica de negócio para templates
 type TemplateUseCase struct {
 	templateRepo domain.TemplateRepository
+	providers    domain.GitProviders
+	events       *EventBus
 }
 
 // NewTemplateUseCase cria uma nova instância do use case de templates
-func NewTemplateUseCase(templateRepo domain.TemplateRepository) *TemplateUseCase {
+func NewTemplateUseCase(templateRepo domain.TemplateRepository, providers domain.GitProviders, events *EventBus) *TemplateUseCase {
 	return &TemplateUseCase{
 		templateRepo: templateRepo,
+		providers:    providers,
+		events:       events,
 	}
 }
 
@@ -26,17 +42,33 @@ func (uc *TemplateUseCase) CreateTemplate(ctx context.Context, req *domain.Creat
 		return nil, errors.New("template with this name already exists")
 	}
 
+	policy := req.RollbackPolicy
+	if policy == "" {
+		policy = domain.RollbackPolicyDelete
+	}
+	if !domain.IsValidRollbackPolicy(policy) {
+		return nil, errors.New("rollback policy must be delete, archive or keep")
+	}
+
 	template := &domain.Template{
-		Name:        req.Name,
-		Description: req.Description,
-		GitURL:      req.GitURL,
-		Language:    req.Language,
-		Tags:        req.Tags,
+		Name:           req.Name,
+		Description:    req.Description,
+		GitURL:         req.GitURL,
+		Ref:            req.Ref,
+		Subdirectory:   req.Subdirectory,
+		Language:       req.Language,
+		Tags:           req.Tags,
+		RollbackPolicy: policy,
+	}
+
+	if err := uc.loadManifest(ctx, template); err != nil {
+		return nil, err
 	}
 
 	if err := uc.templateRepo.Create(ctx, template); err != nil {
 		return nil, err
 	}
+	uc.events.Publish(domain.EventTemplateCreated, template.ID, template)
 
 	return template, nil
 }
@@ -75,8 +107,18 @@ func (uc *TemplateUseCase) UpdateTemplate(ctx context.Context, id uint, req *dom
 	if req.Description != "" {
 		template.Description = req.Description
 	}
-	if req.GitURL != "" {
+	sourceChanged := false
+	if req.GitURL != "" && req.GitURL != template.GitURL {
 		template.GitURL = req.GitURL
+		sourceChanged = true
+	}
+	if req.Ref != nil && *req.Ref != template.Ref {
+		template.Ref = *req.Ref
+		sourceChanged = true
+	}
+	if req.Subdirectory != nil && *req.Subdirectory != template.Subdirectory {
+		template.Subdirectory = *req.Subdirectory
+		sourceChanged = true
 	}
 	if req.Language != "" {
 		template.Language = req.Language
@@ -84,14 +126,103 @@ func (uc *TemplateUseCase) UpdateTemplate(ctx context.Context, id uint, req *dom
 	if req.Tags != "" {
 		template.Tags = req.Tags
 	}
+	if req.RollbackPolicy != "" {
+		if !domain.IsValidRollbackPolicy(req.RollbackPolicy) {
+			return nil, errors.New("rollback policy must be delete, archive or keep")
+		}
+		template.RollbackPolicy = req.RollbackPolicy
+	}
+
+	if sourceChanged {
+		if err := uc.loadManifest(ctx, template); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	uc.events.Publish(domain.EventTemplateUpdated, template.ID, template)
+
+	return template, nil
+}
+
+// RefreshTemplate relê o manifesto do repositório do template
+func (uc *TemplateUseCase) RefreshTemplate(ctx context.Context, id uint) (*domain.Template, error) {
+	template, err := uc.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, errors.New("template not found")
+	}
 
+	if err := uc.loadManifest(ctx, template); err != nil {
+		return nil, err
+	}
 	if err := uc.templateRepo.Update(ctx, template); err != nil {
 		return nil, err
 	}
+	uc.events.Publish(domain.EventTemplateUpdated, template.ID, template)
 
 	return template, nil
 }
 
+// GetTemplateInputs retorna as entradas declaradas no manifesto do template
+func (uc *TemplateUseCase) GetTemplateInputs(ctx context.Context, id uint) ([]domain.TemplateInput, error) {
+	template, err := uc.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template.Manifest == nil {
+		return []domain.TemplateInput{}, nil
+	}
+	return template.Manifest.Inputs, nil
+}
+
+// loadManifest clona o repositório do template e carrega o seu manifesto.
+// Também valida que o subdirectory configurado existe no repositório.
+func (uc *TemplateUseCase) loadManifest(ctx context.Context, template *domain.Template) error {
+	subdir, err := cleanSubdirectory(template.Subdirectory)
+	if err != nil {
+		return err
+	}
+	template.Subdirectory = subdir
+
+	tempDir, err := os.MkdirTemp("", "template-manifest-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	repoDir := filepath.Join(tempDir, "repo")
+	// O clone não depende do provedor de destino, então usa o padrão
+	gitService, err := uc.providers.Get("")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, manifestCloneTimeout)
+	defer cancel()
+	source := domain.CloneSource{GitURL: template.GitURL, Ref: template.Ref, Subdirectory: subdir}
+	if _, err := gitService.CloneRepository(ctx, source, repoDir); err != nil {
+		log.Error().Err(err).Str("git_url", template.GitURL).Msg("failed to clone template for manifest")
+		return fmt.Errorf("failed to clone template repository: %w", err)
+	}
+
+	templateDir := filepath.Join(repoDir, subdir)
+	if info, err := os.Stat(templateDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("subdirectory %q not found in template repository", subdir)
+	}
+
+	m, err := manifest.Load(templateDir)
+	if err != nil {
+		return err
+	}
+	template.Manifest = m
+	return nil
+}
+
 // DeleteTemplate remove um template
 func (uc *TemplateUseCase) DeleteTemplate(ctx context.Context, id uint) error {
 	template, err := uc.templateRepo.GetByID(ctx, id)
@@ -102,5 +233,27 @@ func (uc *TemplateUseCase) DeleteTemplate(ctx context.Context, id uint) error {
 		return errors.New("template not found")
 	}
 
-	return uc.templateRepo.Delete(ctx, id)
+	if err := uc.templateRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	uc.events.Publish(domain.EventTemplateDeleted, id, nil)
+	return nil
+}
+
+// cleanSubdirectory normaliza o subdirectory informado, recusando caminhos
+// absolutos ou que saiam da raiz do repositório
+func cleanSubdirectory(subdir string) (string, error) {
+	subdir = strings.TrimSpace(subdir)
+	if subdir == "" {
+		return "", nil
+	}
+	if filepath.IsAbs(subdir) {
+		return "", fmt.Errorf("invalid subdirectory %q", subdir)
+	}
+	cleaned := filepath.ToSlash(filepath.Clean(subdir))
+	// Um subdiretório iniciado por '-' seria lido como opção do sparse-checkout
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") || strings.HasPrefix(cleaned, "-") {
+		return "", fmt.Errorf("invalid subdirectory %q", subdir)
+	}
+	return cleaned, nil
 }
@@ -10,7 +10,13 @@ import (
 
 // NewDatabase cria uma nova conexão com o banco de dados
 func NewDatabase() (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open("template_manager.db"), &gorm.Config{
+	return Open("template_manager.db")
+}
+
+// Open abre e migra o banco de dados SQLite no caminho informado
+func Open(path string) (*gorm.DB, error) {
+	// O busy timeout evita erros de "database is locked" com vários workers gravando
+	db, err := gorm.Open(sqlite.Open(path+"?_busy_timeout=5000"), &gorm.Config{
 		Logger: gormlogger.Default.LogMode(gormlogger.Warn),
 	})
 	if err != nil {
@@ -18,7 +24,13 @@ func NewDatabase() (*gorm.DB, error) {
 	}
 
 	// Auto migrate das tabelas
-	if err := db.AutoMigrate(&domain.Template{}, &domain.Project{}); err != nil {
+	if err := db.AutoMigrate(&domain.Template{}, &domain.Project{}, &domain.ProjectJob{}, &domain.ProjectStep{}, &domain.AuditEntry{}, &domain.ProjectLog{}); err != nil {
+		return nil, err
+	}
+
+	// Projetos gravados antes da máquina de estados usavam o status "creating";
+	// eles voltam para a fila e são retomados na inicialização
+	if err := db.Model(&domain.Project{}).Where("status = ?", "creating").Update("status", domain.ProjectStatusQueued).Error; err != nil {
 		return nil, err
 	}
 
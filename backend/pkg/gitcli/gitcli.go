@@ -0,0 +1,279 @@
+package gitcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"template-manager-backend/internal/domain"
+)
+
+// DefaultBranch é o branch usado no commit inicial dos projetos gerados
+const DefaultBranch = "main"
+
+// Identidade usada no commit inicial quando o git não tem user.name/user.email
+// configurados, como em containers e ambientes de CI
+const (
+	fallbackName  = "Template Manager"
+	fallbackEmail = "template-manager@localhost"
+)
+
+// stderrTailLines é o número de linhas finais do stderr anexadas ao erro de um
+// comando que falhou
+const stderrTailLines = 5
+
+// Padrões de credenciais removidos da saída do git: usuário e senha em URLs e
+// cabeçalhos de autorização
+var (
+	urlCredentials = regexp.MustCompile(`://[^/\s@]+@`)
+	authHeader     = regexp.MustCompile(`(?i)(authorization:\s*\w+\s+)\S+`)
+)
+
+// refPattern aceita nomes de branch, tag e SHAs; o primeiro caractere não pode
+// ser '-' para que a ref nunca seja lida como opção do git
+var refPattern = regexp.MustCompile(`^[A-Za-z0-9_.][A-Za-z0-9_./-]*$`)
+
+// ValidateRef recusa refs que o git interpretaria como opções ou que não são
+// nomes válidos de branch, tag ou SHA. Uma ref vazia usa o branch padrão.
+func ValidateRef(ref string) error {
+	if ref == "" {
+		return nil
+	}
+	if !refPattern.MatchString(ref) || strings.Contains(ref, "..") || strings.HasSuffix(ref, ".lock") {
+		return fmt.Errorf("invalid ref %q", ref)
+	}
+	return nil
+}
+
+// ValidateURL recusa URLs vazias ou que o git interpretaria como opções
+func ValidateURL(gitURL string) error {
+	if gitURL == "" || strings.HasPrefix(gitURL, "-") {
+		return fmt.Errorf("invalid repository url %q", gitURL)
+	}
+	return nil
+}
+
+// Auth contém as credenciais HTTPS usadas no push
+type Auth struct {
+	Username string
+	Password string
+}
+
+// Clone clona a origem em destPath e retorna o SHA do commit resolvido.
+// Quando a origem tem subdiretório, o clone é parcial e apenas a pasta é extraída.
+func Clone(ctx context.Context, source domain.CloneSource, destPath string) (string, error) {
+	if err := ValidateURL(source.GitURL); err != nil {
+		return "", err
+	}
+	if err := ValidateRef(source.Ref); err != nil {
+		return "", err
+	}
+
+	args := []string{"clone"}
+	if source.Subdirectory != "" {
+		args = append(args, "--filter=blob:none", "--no-checkout")
+	}
+	// "--" impede que a URL ou o destino sejam lidos como opções
+	if err := run(ctx, "", nil, append(args, "--", source.GitURL, destPath)...); err != nil {
+		return "", fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	if source.Subdirectory != "" {
+		if err := run(ctx, destPath, nil, "sparse-checkout", "set", source.Subdirectory); err != nil {
+			return "", fmt.Errorf("failed to configure sparse checkout: %w", err)
+		}
+	}
+
+	// Fazer checkout da ref solicitada (branch, tag ou SHA)
+	if source.Ref != "" || source.Subdirectory != "" {
+		args = []string{"-c", "advice.detachedHead=false", "checkout"}
+		if source.Ref != "" {
+			args = append(args, source.Ref, "--")
+		}
+		if err := run(ctx, destPath, nil, args...); err != nil {
+			return "", fmt.Errorf("failed to checkout ref %s: %w", source.Ref, err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = destPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Push cria o commit inicial em localPath no branch informado (vazio usa
+// DefaultBranch) e envia para repoURL. As credenciais são passadas por
+// cabeçalho HTTP e não ficam gravadas no repositório.
+func Push(ctx context.Context, localPath, repoURL, branch string, auth *Auth) error {
+	if branch == "" {
+		branch = DefaultBranch
+	}
+
+	// Inicializar repositório Git
+	if err := run(ctx, localPath, nil, "init", "--initial-branch="+branch); err != nil {
+		return fmt.Errorf("failed to init git: %w", err)
+	}
+
+	// Adicionar todos os arquivos
+	if err := run(ctx, localPath, nil, "add", "."); err != nil {
+		return fmt.Errorf("failed to add files: %w", err)
+	}
+
+	// Fazer commit inicial
+	if err := run(ctx, localPath, identityEnv(ctx, localPath), "commit", "-m", "Initial commit from template"); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	// Adicionar remote origin
+	if err := run(ctx, localPath, nil, "remote", "add", "origin", repoURL); err != nil {
+		return fmt.Errorf("failed to add remote: %w", err)
+	}
+
+	// Push para o repositório
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if auth != nil && auth.Password != "" {
+		// Configuração via ambiente para que o token não apareça na linha de comando
+		credentials := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+	if err := run(ctx, localPath, env, "push", "-u", "origin", branch); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	return nil
+}
+
+// InitBare cria um repositório bare em path com o branch inicial informado
+// (vazio usa DefaultBranch)
+func InitBare(ctx context.Context, path, branch string) error {
+	if branch == "" {
+		branch = DefaultBranch
+	}
+	// "--" impede que o caminho seja lido como opção
+	if err := run(ctx, "", nil, "init", "--bare", "--initial-branch="+branch, "--", path); err != nil {
+		return fmt.Errorf("failed to init bare repository: %w", err)
+	}
+	return nil
+}
+
+// ClearHistory remove o histórico de commits de um repositório
+func ClearHistory(repoPath string) error {
+	// Remover diretório .git
+	gitDir := filepath.Join(repoPath, ".git")
+	if err := os.RemoveAll(gitDir); err != nil {
+		return fmt.Errorf("failed to remove .git directory: %w", err)
+	}
+
+	return nil
+}
+
+// identityEnv retorna variáveis de autor e committer quando o git não tem
+// identidade configurada; com identidade presente, a configuração é respeitada
+func identityEnv(ctx context.Context, dir string) []string {
+	// As consultas imprimem a identidade, que não interessa ao log do projeto
+	ctx = domain.WithOutput(ctx, nil)
+	var env []string
+	if run(ctx, dir, nil, "config", "user.name") != nil {
+		env = append(env, "GIT_AUTHOR_NAME="+fallbackName, "GIT_COMMITTER_NAME="+fallbackName)
+	}
+	if run(ctx, dir, nil, "config", "user.email") != nil {
+		env = append(env, "GIT_AUTHOR_EMAIL="+fallbackEmail, "GIT_COMMITTER_EMAIL="+fallbackEmail)
+	}
+	return env
+}
+
+// run executa um comando git no diretório informado. A saída é enviada linha a
+// linha para a função de domain.WithOutput, e as últimas linhas do stderr são
+// anexadas ao erro quando o comando falha.
+func run(ctx context.Context, dir string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	output := domain.OutputFrom(ctx)
+	stdout := &lineWriter{output: output}
+	stderr := &lineWriter{output: output, keep: stderrTailLines}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	stdout.flush()
+	stderr.flush()
+
+	if err != nil && len(stderr.tail) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.Join(stderr.tail, "\n"))
+	}
+	return err
+}
+
+// redact remove credenciais de uma linha da saída do git
+func redact(line string) string {
+	line = urlCredentials.ReplaceAllString(line, "://***@")
+	return authHeader.ReplaceAllString(line, "${1}***")
+}
+
+// lineWriter divide a saída de um comando em linhas, enviando cada uma para
+// output e guardando as últimas keep linhas. Barras de progresso do git usam
+// '\r', por isso ele também encerra uma linha.
+type lineWriter struct {
+	mu     sync.Mutex
+	output domain.OutputFunc
+	keep   int
+	buf    []byte
+	tail   []string
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush envia a última linha, quando a saída não termina com quebra de linha
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.emit(string(w.buf))
+	w.buf = nil
+}
+
+func (w *lineWriter) emit(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	line = redact(line)
+	if w.output != nil {
+		w.output(line)
+	}
+	if w.keep > 0 {
+		w.tail = append(w.tail, line)
+		if len(w.tail) > w.keep {
+			w.tail = w.tail[1:]
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gitcli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"testing"
+)
+
+func TestValidateRef(t *testing.T) {
+	valid := []string{"", "main", "v1.2.0", "feature/login", "0123abcd", "release-1"}
+	for _, ref := range valid {
+		if err := ValidateRef(ref); err != nil {
+			t.Errorf("ValidateRef(%q) = %v, want nil", ref, err)
+		}
+	}
+
+	invalid := []string{"-b", "--upload-pack=touch /tmp/x", "main..dev", "a b", "ref.lock", "/main"}
+	for _, ref := range invalid {
+		if err := ValidateRef(ref); err == nil {
+			t.Errorf("ValidateRef(%q) = nil, want error", ref)
+		}
+	}
+}
+
+func TestCloneRejectsOptionLikeArguments(t *testing.T) {
+	dest := t.TempDir()
+	sources := []domain.CloneSource{
+		{GitURL: "--upload-pack=touch /tmp/pwned", Ref: "main"},
+		{GitURL: "https://example.com/repo.git", Ref: "--orphan=x"},
+	}
+	for _, source := range sources {
+		if _, err := Clone(context.Background(), source, dest); err == nil {
+			t.Errorf("Clone(%+v) = nil error, want rejection", source)
+		}
+	}
+}
+
+func TestInitBare(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+
+	repoPath := filepath.Join(dir, "demo.git")
+	if err := InitBare(context.Background(), repoPath, ""); err != nil {
+		t.Fatal(err)
+	}
+	head, err := os.ReadFile(filepath.Join(repoPath, "HEAD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ref: refs/heads/" + DefaultBranch + "\n"; string(head) != want {
+		t.Errorf("HEAD = %q, want %q", head, want)
+	}
+
+	// A falha traz o stderr do git, que também chega à saída do contexto
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var output []string
+	ctx := domain.WithOutput(context.Background(), func(line string) {
+		output = append(output, line)
+	})
+	err = InitBare(ctx, filepath.Join(file, "demo.git"), "")
+	if err == nil || !strings.Contains(err.Error(), "failed to init bare repository") {
+		t.Fatalf("InitBare() error = %v, want the git failure", err)
+	}
+	if len(output) == 0 || !strings.Contains(err.Error(), output[len(output)-1]) {
+		t.Errorf("error %q does not end with the git output %q", err, output)
+	}
+}
@@ -0,0 +1,167 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/gitcli"
+	"template-manager-backend/pkg/gitprovider"
+	"time"
+)
+
+// ProviderName identifica o Gitea/Forgejo no registro de provedores
+const ProviderName = "gitea"
+
+// Config reúne as opções do provedor Gitea/Forgejo
+type Config struct {
+	// BaseURL é a URL da instância, por exemplo https://gitea.example.com
+	BaseURL string
+	Token   string
+	// Owner é a organização onde os repositórios são criados; vazio usa o
+	// usuário dono do token
+	Owner string
+	// Username é usado no push; vazio é resolvido pela API a partir do token
+	Username      string
+	Private       bool
+	DefaultBranch string
+}
+
+// gitService implementa domain.GitService usando a API REST v1 do Gitea
+type gitService struct {
+	client *http.Client
+	config Config
+
+	mu       sync.Mutex
+	username string
+}
+
+type createRepoRequest struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Private       bool   `json:"private"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+type repoResponse struct {
+	ID       int    `json:"id"`
+	CloneURL string `json:"clone_url"`
+}
+
+type editRepoRequest struct {
+	Archived bool `json:"archived"`
+}
+
+type userResponse struct {
+	Login string `json:"login"`
+}
+
+// NewGitService cria uma nova instância do serviço Gitea/Forgejo
+func NewGitService(config Config) domain.GitService {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.DefaultBranch == "" {
+		config.DefaultBranch = gitcli.DefaultBranch
+	}
+
+	return &gitService{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		config:   config,
+		username: config.Username,
+	}
+}
+
+// CloneRepository clona um repositório Git e retorna o commit resolvido
+func (s *gitService) CloneRepository(ctx context.Context, source domain.CloneSource, destPath string) (string, error) {
+	return gitcli.Clone(ctx, source, destPath)
+}
+
+// CreateRepository cria um novo repositório para o usuário ou organização
+func (s *gitService) CreateRepository(ctx context.Context, name, description string) (string, error) {
+	req := createRepoRequest{
+		Name:          name,
+		Description:   description,
+		Private:       s.config.Private,
+		DefaultBranch: s.config.DefaultBranch,
+	}
+
+	path := "/user/repos"
+	if s.config.Owner != "" {
+		path = "/orgs/" + url.PathEscape(s.config.Owner) + "/repos"
+	}
+
+	var repo repoResponse
+	if err := s.do(ctx, http.MethodPost, path, req, &repo); err != nil {
+		return "", fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	return repo.CloneURL, nil
+}
+
+// PushToRepository faz push do código local usando o token via HTTPS
+func (s *gitService) PushToRepository(ctx context.Context, localPath, repoURL string) error {
+	username, err := s.login(ctx)
+	if err != nil {
+		return err
+	}
+	return gitcli.Push(ctx, localPath, repoURL, s.config.DefaultBranch, &gitcli.Auth{Username: username, Password: s.config.Token})
+}
+
+// DeleteRepository remove o repositório identificado pela URL de clone
+func (s *gitService) DeleteRepository(ctx context.Context, repoURL string) error {
+	path, err := gitprovider.RepoPath(s.config.BaseURL, repoURL)
+	if err != nil {
+		return err
+	}
+	owner, name, _ := strings.Cut(path, "/")
+
+	path = "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+	if err := s.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+	return nil
+}
+
+// ArchiveRepository arquiva o repositório identificado pela URL de clone
+func (s *gitService) ArchiveRepository(ctx context.Context, repoURL string) error {
+	path, err := gitprovider.RepoPath(s.config.BaseURL, repoURL)
+	if err != nil {
+		return err
+	}
+	owner, name, _ := strings.Cut(path, "/")
+
+	path = "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+	if err := s.do(ctx, http.MethodPatch, path, editRepoRequest{Archived: true}, nil); err != nil {
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+	return nil
+}
+
+// ClearGitHistory remove o histórico de commits de um repositório
+func (s *gitService) ClearGitHistory(ctx context.Context, repoPath string) error {
+	return gitcli.ClearHistory(repoPath)
+}
+
+// login retorna o usuário dono do token, consultando a API na primeira chamada
+func (s *gitService) login(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.username != "" {
+		return s.username, nil
+	}
+
+	var user userResponse
+	if err := s.do(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return "", fmt.Errorf("failed to resolve token user: %w", err)
+	}
+	s.username = user.Login
+	return s.username, nil
+}
+
+// do executa uma chamada autenticada na API do Gitea
+func (s *gitService) do(ctx context.Context, method, path string, body, out any) error {
+	headers := map[string]string{"Authorization": "token " + s.config.Token}
+	return gitprovider.DoJSON(ctx, s.client, method, s.config.BaseURL+"/api/v1"+path, headers, body, out)
+}
@@ -0,0 +1,121 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"template-manager-backend/pkg/gitprovider/gitprovidertest"
+	"testing"
+)
+
+func TestCreateRepositoryForUserOrOrganization(t *testing.T) {
+	tests := []struct {
+		owner string
+		path  string
+	}{
+		{"", "/api/v1/user/repos"},
+		{"platform", "/api/v1/orgs/platform/repos"},
+	}
+	for _, tt := range tests {
+		server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1, "clone_url": "https://gitea.example.com/platform/demo.git"}`))
+		})
+
+		service := NewGitService(Config{BaseURL: server.URL, Token: "secret", Owner: tt.owner, Private: true})
+		repoURL, err := service.CreateRepository(context.Background(), "demo", "Demo project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repoURL != "https://gitea.example.com/platform/demo.git" {
+			t.Errorf("repoURL = %q", repoURL)
+		}
+
+		got := server.Calls()
+		if len(got) != 1 || got[0].Method != http.MethodPost || got[0].Path != tt.path {
+			t.Fatalf("owner %q: got %+v, want POST %s", tt.owner, got, tt.path)
+		}
+		if auth := got[0].Header.Get("Authorization"); auth != "token secret" {
+			t.Errorf("Authorization = %q, want %q", auth, "token secret")
+		}
+		body := got[0].Body
+		if body["name"] != "demo" || body["private"] != true || body["default_branch"] != "main" {
+			t.Errorf("body = %v", body)
+		}
+	}
+}
+
+func TestLoginIsResolvedOnceFromToken(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		if call.Path != "/api/v1/user" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"login": "bot"}`))
+	})
+
+	service := NewGitService(Config{BaseURL: server.URL, Token: "secret"}).(*gitService)
+	for i := 0; i < 3; i++ {
+		login, err := service.login(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if login != "bot" {
+			t.Fatalf("login = %q, want bot", login)
+		}
+	}
+	got := server.Calls()
+	if len(got) != 1 {
+		t.Fatalf("expected a single /user lookup, got %+v", got)
+	}
+	if auth := got[0].Header.Get("Authorization"); auth != "token secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestLoginUsesConfiguredUsername(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	service := NewGitService(Config{BaseURL: server.URL, Token: "secret", Username: "deploy"}).(*gitService)
+	login, err := service.login(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login != "deploy" || len(server.Calls()) != 0 {
+		t.Fatalf("login = %q with calls %+v, want the configured username without calls", login, server.Calls())
+	}
+}
+
+func TestDeleteAndArchiveRepository(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		if call.Method == http.MethodPatch {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	service := NewGitService(Config{BaseURL: server.URL, Token: "secret"})
+	repoURL := server.URL + "/platform/demo.git"
+	if err := service.DeleteRepository(context.Background(), repoURL); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ArchiveRepository(context.Background(), repoURL); err != nil {
+		t.Fatal(err)
+	}
+
+	got := server.Calls()
+	if len(got) != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	if got[0].Method != http.MethodDelete || got[0].Path != "/api/v1/repos/platform/demo" {
+		t.Errorf("delete call = %s %s", got[0].Method, got[0].Path)
+	}
+	if got[1].Method != http.MethodPatch || got[1].Path != "/api/v1/repos/platform/demo" {
+		t.Errorf("archive call = %s %s", got[1].Method, got[1].Path)
+	}
+	if len(got[1].Body) != 1 || got[1].Body["archived"] != true {
+		t.Errorf("archive body = %v, want {\"archived\":true}", got[1].Body)
+	}
+}
@@ -3,43 +3,52 @@ package github
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"strings"
 	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/gitcli"
+	"template-manager-backend/pkg/gitprovider"
 
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
 )
 
+// ProviderName identifica o GitHub no registro de provedores
+const ProviderName = "github"
+
 // gitService implementa domain.GitService
 type gitService struct {
-	client    *github.Client
-	token     string
-	username  string
+	client   *github.Client
+	token    string
+	username string
 }
 
-// NewGitService cria uma nova instância do serviço Git
-func NewGitService(token, username string) domain.GitService {
+// NewGitService cria uma nova instância do serviço Git.
+// apiURL permite apontar para um GitHub Enterprise; vazio usa github.com.
+func NewGitService(token, username, apiURL string) (domain.GitService, error) {
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	client := github.NewClient(tc)
+	if apiURL != "" {
+		var err error
+		client, err = client.WithEnterpriseURLs(apiURL, apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid github api url: %w", err)
+		}
+	}
 
 	return &gitService{
 		client:   client,
 		token:    token,
 		username: username,
-	}
+	}, nil
 }
 
-// CloneRepository clona um repositório Git
-func (s *gitService) CloneRepository(ctx context.Context, gitURL, destPath string) error {
-	// Usar git command line para clonar (mais confiável)
-	cmd := exec.CommandContext(ctx, "git", "clone", gitURL, destPath)
-	return cmd.Run()
+// CloneRepository clona um repositório Git e retorna o commit resolvido
+func (s *gitService) CloneRepository(ctx context.Context, source domain.CloneSource, destPath string) (string, error) {
+	return gitcli.Clone(ctx, source, destPath)
 }
 
 // CreateRepository cria um novo repositório no GitHub
@@ -50,63 +59,53 @@ func (s *gitService) CreateRepository(ctx context.Context, name, description str
 		Private:     github.Bool(false),
 	}
 
-       owner := ""
-       if s.username != "" {
-               owner = s.username
-       }
-       createdRepo, _, err := s.client.Repositories.Create(ctx, owner, repo)
-       if err != nil {
-               return "", fmt.Errorf("failed to create repository: %w", err)
-       }
+	owner := ""
+	if s.username != "" {
+		owner = s.username
+	}
+	createdRepo, _, err := s.client.Repositories.Create(ctx, owner, repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to create repository: %w", err)
+	}
 
 	return createdRepo.GetCloneURL(), nil
 }
 
 // PushToRepository faz push do código local para um repositório remoto
 func (s *gitService) PushToRepository(ctx context.Context, localPath, repoURL string) error {
-	// Inicializar repositório Git
-	cmd := exec.CommandContext(ctx, "git", "init")
-	cmd.Dir = localPath
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to init git: %w", err)
-	}
+	return gitcli.Push(ctx, localPath, repoURL, "", &gitcli.Auth{Username: s.username, Password: s.token})
+}
 
-	// Adicionar todos os arquivos
-	cmd = exec.CommandContext(ctx, "git", "add", ".")
-	cmd.Dir = localPath
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add files: %w", err)
+// DeleteRepository remove o repositório identificado pela URL de clone
+func (s *gitService) DeleteRepository(ctx context.Context, repoURL string) error {
+	path, err := gitprovider.RepoPath("", repoURL)
+	if err != nil {
+		return err
 	}
+	owner, name, _ := strings.Cut(path, "/")
 
-	// Fazer commit inicial
-	cmd = exec.CommandContext(ctx, "git", "commit", "-m", "Initial commit from template")
-	cmd.Dir = localPath
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+	if _, err := s.client.Repositories.Delete(ctx, owner, name); err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
 	}
+	return nil
+}
 
-	// Adicionar remote origin
-	cmd = exec.CommandContext(ctx, "git", "remote", "add", "origin", repoURL)
-	cmd.Dir = localPath
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add remote: %w", err)
+// ArchiveRepository arquiva o repositório identificado pela URL de clone
+func (s *gitService) ArchiveRepository(ctx context.Context, repoURL string) error {
+	path, err := gitprovider.RepoPath("", repoURL)
+	if err != nil {
+		return err
 	}
+	owner, name, _ := strings.Cut(path, "/")
 
-	// Push para o repositório
-	cmd = exec.CommandContext(ctx, "git", "push", "-u", "origin", "main")
-	cmd.Dir = localPath
-	cmd.Env = append(os.Environ(), fmt.Sprintf("GIT_ASKPASS=echo"), fmt.Sprintf("GIT_USERNAME=%s", s.username), fmt.Sprintf("GIT_PASSWORD=%s", s.token))
-	
-	return cmd.Run()
+	repo := &github.Repository{Archived: github.Bool(true)}
+	if _, _, err := s.client.Repositories.Edit(ctx, owner, name, repo); err != nil {
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+	return nil
 }
 
 // ClearGitHistory remove o histórico de commits de um repositório
 func (s *gitService) ClearGitHistory(ctx context.Context, repoPath string) error {
-	// Remover diretório .git
-	gitDir := filepath.Join(repoPath, ".git")
-	if err := os.RemoveAll(gitDir); err != nil {
-		return fmt.Errorf("failed to remove .git directory: %w", err)
-	}
-
-	return nil
+	return gitcli.ClearHistory(repoPath)
 }
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"template-manager-backend/pkg/gitprovider/gitprovidertest"
+	"testing"
+)
+
+func TestCreateAndDeleteRepositoryWithAPIURL(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		switch call.Method + " " + call.Path {
+		case "POST /api/v3/user/repos":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1, "name": "demo", "clone_url": "https://github.example.com/octo/demo.git"}`))
+		case "DELETE /api/v3/repos/octo/demo":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	service, err := NewGitService("secret", "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repoURL, err := service.CreateRepository(context.Background(), "demo", "Demo project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repoURL != "https://github.example.com/octo/demo.git" {
+		t.Fatalf("repoURL = %q", repoURL)
+	}
+	if err := service.DeleteRepository(context.Background(), repoURL); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := server.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected creation and deletion, got %+v", calls)
+	}
+	for _, call := range calls {
+		if auth := call.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("%s %s sent Authorization %q", call.Method, call.Path, auth)
+		}
+	}
+	body := calls[0].Body
+	if body["name"] != "demo" || body["description"] != "Demo project" || body["private"] != false {
+		t.Errorf("create body = %v", body)
+	}
+}
+
+func TestCreateRepositoryError(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "Repository creation failed."}`))
+	})
+
+	service, err := NewGitService("secret", "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateRepository(context.Background(), "demo", ""); err == nil {
+		t.Fatal("CreateRepository() = nil error, want the API error")
+	}
+}
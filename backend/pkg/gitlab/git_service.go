@@ -0,0 +1,155 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/gitcli"
+	"template-manager-backend/pkg/gitprovider"
+	"time"
+)
+
+// ProviderName identifica o GitLab no registro de provedores
+const ProviderName = "gitlab"
+
+// Config reúne as opções do provedor GitLab
+type Config struct {
+	// BaseURL é a URL da instância, por exemplo https://gitlab.com
+	BaseURL string
+	Token   string
+	// Namespace é o caminho do usuário ou grupo onde os projetos são criados;
+	// vazio usa o namespace pessoal do dono do token
+	Namespace string
+	// Visibility aceita private, internal ou public
+	Visibility    string
+	DefaultBranch string
+}
+
+// gitService implementa domain.GitService usando a API REST v4 do GitLab
+type gitService struct {
+	client *http.Client
+	config Config
+}
+
+type namespaceResponse struct {
+	ID int `json:"id"`
+}
+
+type createProjectRequest struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	Description   string `json:"description,omitempty"`
+	Visibility    string `json:"visibility,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	NamespaceID   int    `json:"namespace_id,omitempty"`
+}
+
+type projectResponse struct {
+	ID            int    `json:"id"`
+	HTTPURLToRepo string `json:"http_url_to_repo"`
+}
+
+// NewGitService cria uma nova instância do serviço GitLab
+func NewGitService(config Config) domain.GitService {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.BaseURL == "" {
+		config.BaseURL = "https://gitlab.com"
+	}
+	if config.Visibility == "" {
+		config.Visibility = "private"
+	}
+	if config.DefaultBranch == "" {
+		config.DefaultBranch = gitcli.DefaultBranch
+	}
+
+	return &gitService{
+		client: &http.Client{Timeout: 30 * time.Second},
+		config: config,
+	}
+}
+
+// CloneRepository clona um repositório Git e retorna o commit resolvido
+func (s *gitService) CloneRepository(ctx context.Context, source domain.CloneSource, destPath string) (string, error) {
+	return gitcli.Clone(ctx, source, destPath)
+}
+
+// CreateRepository cria um novo projeto no GitLab
+func (s *gitService) CreateRepository(ctx context.Context, name, description string) (string, error) {
+	req := createProjectRequest{
+		Name:          name,
+		Path:          name,
+		Description:   description,
+		Visibility:    s.config.Visibility,
+		DefaultBranch: s.config.DefaultBranch,
+	}
+
+	if s.config.Namespace != "" {
+		namespaceID, err := s.namespaceID(ctx)
+		if err != nil {
+			return "", err
+		}
+		req.NamespaceID = namespaceID
+	}
+
+	var project projectResponse
+	if err := s.do(ctx, http.MethodPost, "/projects", req, &project); err != nil {
+		return "", fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	return project.HTTPURLToRepo, nil
+}
+
+// PushToRepository faz push do código local usando o token via HTTPS
+func (s *gitService) PushToRepository(ctx context.Context, localPath, repoURL string) error {
+	return gitcli.Push(ctx, localPath, repoURL, s.config.DefaultBranch, &gitcli.Auth{Username: "oauth2", Password: s.config.Token})
+}
+
+// DeleteRepository remove o projeto identificado pela URL de clone
+func (s *gitService) DeleteRepository(ctx context.Context, repoURL string) error {
+	path, err := gitprovider.RepoPath(s.config.BaseURL, repoURL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.do(ctx, http.MethodDelete, "/projects/"+url.PathEscape(path), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+	return nil
+}
+
+// ArchiveRepository arquiva o projeto identificado pela URL de clone
+func (s *gitService) ArchiveRepository(ctx context.Context, repoURL string) error {
+	path, err := gitprovider.RepoPath(s.config.BaseURL, repoURL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.do(ctx, http.MethodPost, "/projects/"+url.PathEscape(path)+"/archive", nil, nil); err != nil {
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+	return nil
+}
+
+// ClearGitHistory remove o histórico de commits de um repositório
+func (s *gitService) ClearGitHistory(ctx context.Context, repoPath string) error {
+	return gitcli.ClearHistory(repoPath)
+}
+
+// namespaceID resolve o ID do usuário ou grupo configurado
+func (s *gitService) namespaceID(ctx context.Context) (int, error) {
+	var namespace namespaceResponse
+	path := "/namespaces/" + url.PathEscape(s.config.Namespace)
+	if err := s.do(ctx, http.MethodGet, path, nil, &namespace); err != nil {
+		return 0, fmt.Errorf("failed to resolve namespace %s: %w", s.config.Namespace, err)
+	}
+	return namespace.ID, nil
+}
+
+// do executa uma chamada autenticada na API do GitLab
+func (s *gitService) do(ctx context.Context, method, path string, body, out any) error {
+	headers := map[string]string{"PRIVATE-TOKEN": s.config.Token}
+	return gitprovider.DoJSON(ctx, s.client, method, s.config.BaseURL+"/api/v4"+path, headers, body, out)
+}
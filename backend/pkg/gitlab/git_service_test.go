@@ -0,0 +1,140 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"template-manager-backend/pkg/gitprovider"
+	"template-manager-backend/pkg/gitprovider/gitprovidertest"
+	"testing"
+)
+
+func TestCreateRepositoryInNamespace(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		switch call.Method + " " + call.Path {
+		case "GET /api/v4/namespaces/group%2Fsub":
+			w.Write([]byte(`{"id": 42}`))
+		case "POST /api/v4/projects":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 7, "http_url_to_repo": "https://gitlab.example.com/group/sub/demo.git"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	service := NewGitService(Config{
+		BaseURL:       server.URL,
+		Token:         "secret",
+		Namespace:     "group/sub",
+		Visibility:    "internal",
+		DefaultBranch: "trunk",
+	})
+	repoURL, err := service.CreateRepository(context.Background(), "demo", "Demo project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repoURL != "https://gitlab.example.com/group/sub/demo.git" {
+		t.Fatalf("repoURL = %q", repoURL)
+	}
+
+	calls := server.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected namespace lookup and creation, got %+v", calls)
+	}
+	for _, call := range calls {
+		if token := call.Header.Get("PRIVATE-TOKEN"); token != "secret" {
+			t.Errorf("%s %s sent PRIVATE-TOKEN %q", call.Method, call.Path, token)
+		}
+	}
+	body := calls[1].Body
+	want := map[string]any{
+		"name":           "demo",
+		"path":           "demo",
+		"description":    "Demo project",
+		"visibility":     "internal",
+		"default_branch": "trunk",
+		"namespace_id":   float64(42),
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Errorf("body[%q] = %v, want %v", key, body[key], value)
+		}
+	}
+}
+
+func TestCreateRepositoryWithoutNamespace(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7, "http_url_to_repo": "https://gitlab.example.com/user/demo.git"}`))
+	})
+
+	service := NewGitService(Config{BaseURL: server.URL, Token: "secret"})
+	if _, err := service.CreateRepository(context.Background(), "demo", ""); err != nil {
+		t.Fatal(err)
+	}
+	calls := server.Calls()
+	if len(calls) != 1 || calls[0].Path != "/api/v4/projects" {
+		t.Fatalf("expected only the creation call, got %+v", calls)
+	}
+	body := calls[0].Body
+	if _, ok := body["namespace_id"]; ok {
+		t.Errorf("namespace_id sent without a namespace: %v", body)
+	}
+	if body["visibility"] != "private" || body["default_branch"] != "main" {
+		t.Errorf("expected default visibility and branch, got %v", body)
+	}
+}
+
+func TestDeleteAndArchiveEscapeProjectPath(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	service := NewGitService(Config{BaseURL: server.URL, Token: "secret"})
+	repoURL := server.URL + "/group/name.git"
+	if err := service.DeleteRepository(context.Background(), repoURL); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ArchiveRepository(context.Background(), repoURL); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"DELETE /api/v4/projects/group%2Fname",
+		"POST /api/v4/projects/group%2Fname/archive",
+	}
+	calls := server.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("got %+v, want %v", calls, want)
+	}
+	for i, call := range calls {
+		if got := call.Method + " " + call.Path; got != want[i] {
+			t.Errorf("call %d = %s, want %s", i, got, want[i])
+		}
+		if token := call.Header.Get("PRIVATE-TOKEN"); token != "secret" {
+			t.Errorf("%s sent PRIVATE-TOKEN %q", want[i], token)
+		}
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	server := gitprovidertest.NewServer(t, func(w http.ResponseWriter, call gitprovidertest.Call) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "403 Forbidden"}`))
+	})
+
+	service := NewGitService(Config{BaseURL: server.URL, Token: "secret", Namespace: "group"})
+	_, createErr := service.CreateRepository(context.Background(), "demo", "")
+	deleteErr := service.DeleteRepository(context.Background(), server.URL+"/group/demo.git")
+	archiveErr := service.ArchiveRepository(context.Background(), server.URL+"/group/demo.git")
+
+	for _, err := range []error{createErr, deleteErr, archiveErr} {
+		var apiErr *gitprovider.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *gitprovider.APIError", err)
+		}
+		if apiErr.StatusCode != http.StatusForbidden || apiErr.Message != "403 Forbidden" {
+			t.Errorf("apiErr = %+v", apiErr)
+		}
+	}
+}
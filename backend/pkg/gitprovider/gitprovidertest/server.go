@@ -0,0 +1,57 @@
+// Package gitprovidertest oferece uma API HTTP falsa que registra as chamadas
+// recebidas, usada nos testes dos provedores Git
+package gitprovidertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Call é uma chamada recebida pelo servidor
+type Call struct {
+	Method string
+	// Path é o caminho ainda escapado, como em group%2Fname
+	Path   string
+	Header http.Header
+	// Body é o corpo JSON decodificado, nil quando a requisição não tem corpo
+	Body map[string]any
+}
+
+// Server é um httptest.Server que registra as chamadas e delega a resposta
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewServer inicia um servidor que registra cada chamada e responde com
+// respond. O servidor é encerrado ao fim do teste.
+func NewServer(t *testing.T, respond func(w http.ResponseWriter, call Call)) *Server {
+	t.Helper()
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := Call{Method: r.Method, Path: r.URL.EscapedPath(), Header: r.Header.Clone()}
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&call.Body); err != nil {
+				t.Errorf("invalid request body: %v", err)
+			}
+		}
+		s.mu.Lock()
+		s.calls = append(s.calls, call)
+		s.mu.Unlock()
+		respond(w, call)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Calls retorna uma cópia das chamadas recebidas até o momento
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
@@ -0,0 +1,61 @@
+package gitprovider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"template-manager-backend/internal/domain"
+)
+
+// Registry implementa domain.GitProviders mantendo os serviços por nome
+type Registry struct {
+	mu          sync.RWMutex
+	services    map[string]domain.GitService
+	defaultName string
+}
+
+// NewRegistry cria um registro vazio com o provedor padrão informado
+func NewRegistry(defaultName string) *Registry {
+	return &Registry{
+		services:    make(map[string]domain.GitService),
+		defaultName: defaultName,
+	}
+}
+
+// Register adiciona ou substitui o serviço de um provedor
+func (r *Registry) Register(name string, service domain.GitService) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services[name] = service
+}
+
+// Get retorna o serviço do provedor; nome vazio resolve para o provedor padrão
+func (r *Registry) Get(name string) (domain.GitService, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	service, ok := r.services[name]
+	if !ok {
+		return nil, fmt.Errorf("git provider %q is not configured", name)
+	}
+	return service, nil
+}
+
+// Default retorna o nome do provedor padrão
+func (r *Registry) Default() string {
+	return r.defaultName
+}
+
+// Names retorna os nomes dos provedores registrados em ordem alfabética
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.services))
+	for name := range r.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package gitprovider
+
+import (
+	"template-manager-backend/internal/domain"
+	"testing"
+)
+
+// fakeService identifica um provedor registrado nos testes
+type fakeService struct {
+	domain.GitService
+	name string
+}
+
+func TestRegistryGet(t *testing.T) {
+	registry := NewRegistry("github")
+	github := &fakeService{name: "github"}
+	gitlab := &fakeService{name: "gitlab"}
+	registry.Register("github", github)
+	registry.Register("gitlab", gitlab)
+
+	service, err := registry.Get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service != github {
+		t.Errorf("Get(\"\") = %v, want the default provider", service)
+	}
+
+	service, err = registry.Get("gitlab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service != gitlab {
+		t.Errorf("Get(\"gitlab\") = %v, want gitlab", service)
+	}
+
+	if _, err := registry.Get("bitbucket"); err == nil {
+		t.Error("Get(\"bitbucket\") = nil error, want unknown provider error")
+	}
+}
+
+func TestRegistryWithoutDefaultProvider(t *testing.T) {
+	registry := NewRegistry("gitea")
+	registry.Register("github", &fakeService{name: "github"})
+
+	if _, err := registry.Get(""); err == nil {
+		t.Error("Get(\"\") = nil error, want error for an unconfigured default")
+	}
+	if names := registry.Names(); len(names) != 1 || names[0] != "github" {
+		t.Errorf("Names() = %v, want [github]", names)
+	}
+}
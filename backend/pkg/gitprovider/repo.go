@@ -0,0 +1,31 @@
+package gitprovider
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RepoPath extrai o caminho "dono/nome" de uma URL de clone HTTPS. Quando
+// baseURL tem caminho, como em instâncias servidas em subpasta, o prefixo é
+// removido.
+func RepoPath(baseURL, repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository url: %w", err)
+	}
+
+	path := strings.Trim(parsed.Path, "/")
+	if baseURL != "" {
+		if base, err := url.Parse(baseURL); err == nil {
+			path = strings.TrimPrefix(path, strings.Trim(base.Path, "/"))
+			path = strings.Trim(path, "/")
+		}
+	}
+	path = strings.TrimSuffix(path, ".git")
+
+	if !strings.Contains(path, "/") {
+		return "", fmt.Errorf("invalid repository url: %s", repoURL)
+	}
+	return path, nil
+}
@@ -0,0 +1,105 @@
+package localgit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"template-manager-backend/pkg/gitcli"
+)
+
+// ProviderName identifica o provedor local no registro de provedores
+const ProviderName = "local"
+
+// gitService implementa domain.GitService criando repositórios bare no disco,
+// o que permite gerar projetos sem acesso à rede
+type gitService struct {
+	baseDir string
+}
+
+// NewGitService cria uma nova instância do serviço local em baseDir
+func NewGitService(baseDir string) (domain.GitService, error) {
+	absDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create repositories directory: %w", err)
+	}
+
+	return &gitService{baseDir: absDir}, nil
+}
+
+// CloneRepository clona um repositório Git e retorna o commit resolvido
+func (s *gitService) CloneRepository(ctx context.Context, source domain.CloneSource, destPath string) (string, error) {
+	return gitcli.Clone(ctx, source, destPath)
+}
+
+// CreateRepository cria um repositório bare e retorna sua URL file://
+func (s *gitService) CreateRepository(ctx context.Context, name, description string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid repository name %q", name)
+	}
+
+	repoPath := filepath.Join(s.baseDir, name+".git")
+	if _, err := os.Stat(repoPath); err == nil {
+		return "", fmt.Errorf("failed to create repository: %s already exists", repoPath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	if err := gitcli.InitBare(ctx, repoPath, ""); err != nil {
+		return "", fmt.Errorf("failed to create repository: %w", err)
+	}
+	if description != "" {
+		// Mesmo arquivo usado pelo gitweb para descrever o repositório
+		os.WriteFile(filepath.Join(repoPath, "description"), []byte(description+"\n"), 0o644)
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(repoPath)}).String(), nil
+}
+
+// PushToRepository faz push do código local para o repositório bare
+func (s *gitService) PushToRepository(ctx context.Context, localPath, repoURL string) error {
+	return gitcli.Push(ctx, localPath, repoURL, "", nil)
+}
+
+// DeleteRepository remove o repositório bare indicado pela URL file://
+func (s *gitService) DeleteRepository(ctx context.Context, repoURL string) error {
+	repoPath, err := s.repoPath(repoURL)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(repoPath); err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+	return nil
+}
+
+// ArchiveRepository não é suportado por repositórios bare locais
+func (s *gitService) ArchiveRepository(ctx context.Context, repoURL string) error {
+	return errors.New("the local provider does not support archiving repositories")
+}
+
+// repoPath converte a URL file:// em caminho, recusando caminhos fora de baseDir
+func (s *gitService) repoPath(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Scheme != "file" {
+		return "", fmt.Errorf("invalid repository url: %s", repoURL)
+	}
+
+	repoPath := filepath.Clean(filepath.FromSlash(parsed.Path))
+	if filepath.Dir(repoPath) != s.baseDir || !strings.HasSuffix(repoPath, ".git") {
+		return "", fmt.Errorf("repository %s is outside %s", repoPath, s.baseDir)
+	}
+	return repoPath, nil
+}
+
+// ClearGitHistory remove o histórico de commits de um repositório
+func (s *gitService) ClearGitHistory(ctx context.Context, repoPath string) error {
+	return gitcli.ClearHistory(repoPath)
+}
@@ -0,0 +1,295 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"template-manager-backend/pkg/render"
+	"unicode"
+)
+
+// Expression é uma condição compilada a partir do campo "when" de uma regra.
+//
+// A gramática suportada é pequena de propósito:
+//
+//	expr    = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" expr ")" | compare
+//	compare = operand [ ("==" | "!=") operand ]
+//	operand = identificador | "texto" | 'texto' | número | true | false
+//
+// Identificadores são sempre variáveis; textos precisam de aspas, como em
+// frontend == "none". Uma variável sem valor vale nil.
+type Expression struct {
+	root   node
+	idents []string
+}
+
+// CompileExpression interpreta uma condição
+func CompileExpression(src string) (*Expression, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in expression %q", p.tokens[p.pos].text, src)
+	}
+	return &Expression{root: root, idents: p.idents}, nil
+}
+
+// Identifiers retorna os nomes das variáveis usadas na condição
+func (e *Expression) Identifiers() []string {
+	return e.idents
+}
+
+// Eval avalia a condição com as respostas informadas
+func (e *Expression) Eval(vars map[string]any) bool {
+	return truthy(e.root.eval(vars))
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenLiteral
+	tokenOp
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"),
+			strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="):
+			tokens = append(tokens, token{kind: tokenOp, text: src[i : i+2]})
+			i += 2
+		case c == '!' || c == '(' || c == ')':
+			tokens = append(tokens, token{kind: tokenOp, text: string(c)})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in expression %q", src)
+			}
+			text := src[i+1 : i+1+end]
+			tokens = append(tokens, token{kind: tokenLiteral, text: text, value: text})
+			i += end + 2
+		case isIdentRune(rune(c)) || c == '-':
+			start := i
+			for i < len(src) && (isIdentRune(rune(src[i])) || src[i] == '-' || src[i] == '.') {
+				i++
+			}
+			text := src[start:i]
+			tokens = append(tokens, classify(text))
+		default:
+			return nil, fmt.Errorf("unexpected character %q in expression %q", c, src)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// classify distingue identificadores de literais numéricos e booleanos
+func classify(text string) token {
+	switch text {
+	case "true":
+		return token{kind: tokenLiteral, text: text, value: true}
+	case "false":
+		return token{kind: tokenLiteral, text: text, value: false}
+	}
+	if n, err := strconv.Atoi(text); err == nil {
+		return token{kind: tokenLiteral, text: text, value: n}
+	}
+	return token{kind: tokenIdent, text: text}
+}
+
+type node interface {
+	eval(vars map[string]any) any
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	idents []string
+}
+
+func (p *parser) peekOp(op string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOp && p.tokens[p.pos].text == op
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peekOp("!") {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if p.peekOp("(") {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekOp(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.peekOp(op) {
+			p.pos++
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return compareNode{left: left, right: right, negate: op == "!="}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	switch t.kind {
+	case tokenIdent:
+		p.pos++
+		p.idents = append(p.idents, t.text)
+		return identNode(t.text), nil
+	case tokenLiteral:
+		p.pos++
+		return literalNode{t.value}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in expression", t.text)
+}
+
+type identNode string
+
+func (n identNode) eval(vars map[string]any) any { return vars[string(n)] }
+
+type literalNode struct{ value any }
+
+func (n literalNode) eval(map[string]any) any { return n.value }
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(vars map[string]any) any { return !truthy(n.operand.eval(vars)) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(vars map[string]any) any {
+	return truthy(n.left.eval(vars)) && truthy(n.right.eval(vars))
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(vars map[string]any) any {
+	return truthy(n.left.eval(vars)) || truthy(n.right.eval(vars))
+}
+
+type compareNode struct {
+	left, right node
+	negate      bool
+}
+
+// eval compara os operandos pela sua representação textual, de modo que
+// respostas "true" e true, ou "8080" e 8080, sejam consideradas iguais
+func (n compareNode) eval(vars map[string]any) any {
+	equal := compareText(n.left.eval(vars)) == compareText(n.right.eval(vars))
+	return equal != n.negate
+}
+
+// compareText retorna a representação textual de um operando; uma variável
+// sem valor equivale ao texto vazio
+func compareText(value any) string {
+	if value == nil {
+		return ""
+	}
+	return render.Format(value)
+}
+
+// truthy converte um valor para booleano
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err == nil {
+			return b
+		}
+		return v != ""
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]any{
+		"use_docker": true,
+		"database":   "postgres",
+		"replicas":   3,
+		"empty":      "",
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"use_docker", true},
+		{"!use_docker", false},
+		{`database == "postgres"`, true},
+		{`database != 'postgres'`, false},
+		{"replicas == 3", true},
+		{`replicas == "3"`, true},
+		{"use_docker == true", true},
+		{`database == "mysql" || replicas == 3`, true},
+		{`database == "mysql" || use_docker && replicas == 2`, false},
+		{`(database == "mysql" || use_docker) && replicas == 3`, true},
+		{"empty", false},
+		{"missing", false},
+		{`missing == ""`, true},
+	}
+	for _, tt := range tests {
+		expr, err := CompileExpression(tt.expr)
+		if err != nil {
+			t.Fatalf("CompileExpression(%q) error = %v", tt.expr, err)
+		}
+		if got := expr.Eval(vars); got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileExpressionErrors(t *testing.T) {
+	for _, src := range []string{"", "a ==", "(a", "a b", `a == "open`, "a & b"} {
+		if _, err := CompileExpression(src); err == nil {
+			t.Errorf("CompileExpression(%q) = nil error, want a syntax error", src)
+		}
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	expr, err := CompileExpression(`database == "none" || !use_docker && ProjectName != ""`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"database", "use_docker", "ProjectName"}
+	if got := expr.Identifiers(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Identifiers() = %q, want %q", got, want)
+	}
+}
+
+func TestUnknownIdentifierIsRejected(t *testing.T) {
+	// Sem aspas, none é um identificador e não o texto "none"
+	_, err := Parse([]byte(`
+inputs:
+  - name: frontend
+rules:
+  - exclude: ["frontend/**"]
+    when: frontend == none
+`))
+	if err == nil || !strings.Contains(err.Error(), `unknown identifier "none"`) {
+		t.Fatalf("Parse() error = %v, want an unknown identifier error", err)
+	}
+
+	// Um manifesto lido do banco não passa por Parse
+	m := &domain.TemplateManifest{
+		Inputs: []domain.TemplateInput{{Name: "use_docker", Type: domain.InputTypeBool}},
+		Rules:  []domain.TemplateRule{{Include: []string{"Dockerfile"}, When: "use_dokcer"}},
+	}
+	if _, err := ApplyRules(t.TempDir(), m, nil); err == nil || !strings.Contains(err.Error(), `unknown identifier "use_dokcer"`) {
+		t.Fatalf("ApplyRules() error = %v, want an unknown identifier error", err)
+	}
+}
+
+func TestCompareLargeJSONNumber(t *testing.T) {
+	expr, err := CompileExpression("replicas == 1000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Números decodificados de JSON chegam como float64
+	if !expr.Eval(map[string]any{"replicas": float64(1000000)}) {
+		t.Fatal("expected float64 1000000 to equal literal 1000000")
+	}
+	if expr.Eval(map[string]any{"replicas": float64(1000001)}) {
+		t.Fatal("expected float64 1000001 to differ from literal 1000000")
+	}
+}
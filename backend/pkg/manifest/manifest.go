@@ -0,0 +1,220 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"template-manager-backend/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName é o nome do manifesto esperado na raiz do repositório do template
+const FileName = "template.yaml"
+
+// Variáveis embutidas, disponíveis em todo template sem declaração no manifesto
+const (
+	ProjectNameVariable  = "ProjectName"
+	TemplateNameVariable = "TemplateName"
+)
+
+// ValidationError agrupa os problemas encontrados ao validar as respostas
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid template variables: " + strings.Join(e.Problems, "; ")
+}
+
+// Load lê o manifesto do diretório informado. Retorna nil quando o arquivo não existe.
+func Load(dir string) (*domain.TemplateManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse interpreta e valida o conteúdo de um manifesto
+func Parse(data []byte) (*domain.TemplateManifest, error) {
+	var m domain.TemplateManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	seen := make(map[string]bool, len(m.Inputs))
+	for i := range m.Inputs {
+		input := &m.Inputs[i]
+		if input.Name == "" {
+			return nil, fmt.Errorf("manifest input #%d has no name", i+1)
+		}
+		if seen[input.Name] {
+			return nil, fmt.Errorf("manifest input %q is declared more than once", input.Name)
+		}
+		seen[input.Name] = true
+
+		if input.Type == "" {
+			input.Type = domain.InputTypeString
+		}
+		switch input.Type {
+		case domain.InputTypeString, domain.InputTypeBool, domain.InputTypeInt:
+		case domain.InputTypeChoice:
+			if len(input.Choices) == 0 {
+				return nil, fmt.Errorf("manifest input %q of type choice has no choices", input.Name)
+			}
+		default:
+			return nil, fmt.Errorf("manifest input %q has unsupported type %q", input.Name, input.Type)
+		}
+
+		if input.Regex != "" {
+			pattern, err := regexp.Compile(input.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("manifest input %q has invalid regex: %w", input.Name, err)
+			}
+			input.Pattern = pattern
+		}
+		if input.Default != nil {
+			value, err := coerce(*input, input.Default)
+			if err != nil {
+				return nil, fmt.Errorf("manifest input %q has invalid default: %w", input.Name, err)
+			}
+			input.Default = value
+		}
+	}
+
+	for i, rule := range m.Rules {
+		if len(rule.Include) == 0 && len(rule.Exclude) == 0 {
+			return nil, fmt.Errorf("manifest rule #%d has no include or exclude paths", i+1)
+		}
+		if _, err := compileCondition(&m, rule.When); err != nil {
+			return nil, fmt.Errorf("manifest rule #%d has invalid condition: %w", i+1, err)
+		}
+	}
+
+	return &m, nil
+}
+
+// Resolve valida as respostas contra o manifesto, aplicando valores padrão e
+// convertendo cada valor para o tipo declarado. Respostas não declaradas no
+// manifesto são mantidas como estão.
+func Resolve(m *domain.TemplateManifest, answers map[string]any) (map[string]any, error) {
+	resolved := make(map[string]any, len(answers))
+	for key, value := range answers {
+		resolved[key] = value
+	}
+	if m == nil {
+		return resolved, nil
+	}
+
+	var problems []string
+	for _, input := range m.Inputs {
+		value, ok := answers[input.Name]
+		if !ok || value == nil || value == "" {
+			if input.Default != nil {
+				resolved[input.Name] = input.Default
+				continue
+			}
+			if input.Required {
+				problems = append(problems, fmt.Sprintf("%s is required", input.Name))
+			}
+			delete(resolved, input.Name)
+			continue
+		}
+
+		typed, err := coerce(input, value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", input.Name, err))
+			continue
+		}
+		if input.Regex != "" {
+			pattern, err := inputPattern(input)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s has invalid regex: %v", input.Name, err))
+				continue
+			}
+			if !pattern.MatchString(fmt.Sprint(typed)) {
+				problems = append(problems, fmt.Sprintf("%s does not match %s", input.Name, input.Regex))
+				continue
+			}
+		}
+		resolved[input.Name] = typed
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return resolved, nil
+}
+
+// inputPattern retorna a regex compilada da entrada. Manifestos lidos do banco
+// não passam por Parse e chegam sem Pattern; a regex é compilada aqui, com
+// erro em vez de pânico se for inválida.
+func inputPattern(input domain.TemplateInput) (*regexp.Regexp, error) {
+	if input.Pattern != nil {
+		return input.Pattern, nil
+	}
+	return regexp.Compile(input.Regex)
+}
+
+// coerce converte o valor para o tipo declarado pela entrada
+func coerce(input domain.TemplateInput, value any) (any, error) {
+	switch input.Type {
+	case domain.InputTypeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("expected a boolean, got %q", v)
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected a boolean, got %v", value)
+	case domain.InputTypeInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("expected an integer, got %v", v)
+			}
+			return int(v), nil
+		case string:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("expected an integer, got %q", v)
+			}
+			return n, nil
+		}
+		return nil, fmt.Errorf("expected an integer, got %v", value)
+	case domain.InputTypeChoice:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected one of %s, got %v", strings.Join(input.Choices, ", "), value)
+		}
+		for _, choice := range input.Choices {
+			if s == choice {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("expected one of %s, got %q", strings.Join(input.Choices, ", "), s)
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %v", value)
+		}
+		return s, nil
+	}
+}
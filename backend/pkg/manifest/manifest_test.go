@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"template-manager-backend/internal/domain"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			yaml: `
+inputs:
+  - name: service_name
+    regex: "^[a-z-]+$"
+    required: true
+  - name: replicas
+    type: int
+    default: 2
+  - name: database
+    type: choice
+    choices: [postgres, mysql]
+rules:
+  - include: ["docker/**"]
+    when: database == "postgres"
+`,
+		},
+		{name: "missing name", yaml: "inputs:\n  - type: string\n", wantErr: "has no name"},
+		{name: "duplicate", yaml: "inputs:\n  - name: a\n  - name: a\n", wantErr: "declared more than once"},
+		{name: "unsupported type", yaml: "inputs:\n  - name: a\n    type: float\n", wantErr: "unsupported type"},
+		{name: "choice without choices", yaml: "inputs:\n  - name: a\n    type: choice\n", wantErr: "has no choices"},
+		{name: "invalid regex", yaml: "inputs:\n  - name: a\n    regex: \"[a-\"\n", wantErr: "invalid regex"},
+		{name: "invalid default", yaml: "inputs:\n  - name: a\n    type: int\n    default: many\n", wantErr: "invalid default"},
+		{name: "rule without paths", yaml: "rules:\n  - when: a\n", wantErr: "no include or exclude"},
+		{name: "invalid condition", yaml: "rules:\n  - include: [a]\n    when: \"a ==\"\n", wantErr: "invalid condition"},
+		{name: "unknown identifier", yaml: "inputs:\n  - name: a\nrules:\n  - include: [a]\n    when: b\n", wantErr: `unknown identifier "b"`},
+		{name: "invalid yaml", yaml: "inputs: [", wantErr: "failed to parse manifest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Inputs[0].Type != domain.InputTypeString || m.Inputs[0].Pattern == nil {
+				t.Errorf("first input = %+v, want string type with a compiled pattern", m.Inputs[0])
+			}
+			if m.Inputs[1].Default != 2 {
+				t.Errorf("default = %#v, want int 2", m.Inputs[1].Default)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	m, err := Parse([]byte(`
+inputs:
+  - name: service_name
+    regex: "^[a-z-]+$"
+    required: true
+  - name: replicas
+    type: int
+    default: 2
+  - name: use_docker
+    type: bool
+  - name: database
+    type: choice
+    choices: [postgres, mysql]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		answers  map[string]any
+		want     map[string]any
+		problems []string
+	}{
+		{
+			name:    "coerces types",
+			answers: map[string]any{"service_name": "api", "replicas": "3", "use_docker": "true", "database": "mysql"},
+			want:    map[string]any{"service_name": "api", "replicas": 3, "use_docker": true, "database": "mysql"},
+		},
+		{
+			name:    "json numbers",
+			answers: map[string]any{"service_name": "api", "replicas": float64(4), "use_docker": false},
+			want:    map[string]any{"service_name": "api", "replicas": 4, "use_docker": false},
+		},
+		{
+			name:    "defaults and undeclared answers",
+			answers: map[string]any{"service_name": "api", "replicas": "", "extra": "kept"},
+			want:    map[string]any{"service_name": "api", "replicas": 2, "extra": "kept"},
+		},
+		{
+			name:     "required",
+			answers:  map[string]any{},
+			problems: []string{"service_name is required"},
+		},
+		{
+			name:     "regex mismatch",
+			answers:  map[string]any{"service_name": "My API"},
+			problems: []string{"service_name does not match ^[a-z-]+$"},
+		},
+		{
+			name:     "invalid choice",
+			answers:  map[string]any{"service_name": "api", "database": "oracle"},
+			problems: []string{`database: expected one of postgres, mysql, got "oracle"`},
+		},
+		{
+			name:    "invalid types",
+			answers: map[string]any{"service_name": "api", "replicas": 1.5, "use_docker": "maybe"},
+			problems: []string{
+				"replicas: expected an integer, got 1.5",
+				`use_docker: expected a boolean, got "maybe"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(m, tt.answers)
+			if tt.problems != nil {
+				var validation *ValidationError
+				if !errors.As(err, &validation) {
+					t.Fatalf("Resolve() error = %v, want a ValidationError", err)
+				}
+				if !reflect.DeepEqual(validation.Problems, tt.problems) {
+					t.Fatalf("problems = %q, want %q", validation.Problems, tt.problems)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Resolve() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveInvalidStoredRegex(t *testing.T) {
+	// Um manifesto lido do banco não passa por Parse
+	m := &domain.TemplateManifest{Inputs: []domain.TemplateInput{
+		{Name: "service_name", Type: domain.InputTypeString, Regex: "[a-"},
+	}}
+
+	_, err := Resolve(m, map[string]any{"service_name": "api"})
+	var validation *ValidationError
+	if !errors.As(err, &validation) || !strings.Contains(validation.Error(), "invalid regex") {
+		t.Fatalf("Resolve() error = %v, want an invalid regex problem", err)
+	}
+}
@@ -0,0 +1,146 @@
+package manifest
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"template-manager-backend/internal/domain"
+)
+
+// ApplyRules remove de dir os arquivos excluídos pelas regras do manifesto,
+// avaliadas com as variáveis informadas. Retorna os caminhos removidos.
+func ApplyRules(dir string, m *domain.TemplateManifest, vars map[string]any) ([]string, error) {
+	if m == nil || len(m.Rules) == 0 {
+		return nil, nil
+	}
+
+	active := make([]bool, len(m.Rules))
+	for i, rule := range m.Rules {
+		expr, err := compileCondition(m, rule.When)
+		if err != nil {
+			return nil, fmt.Errorf("rule #%d: %w", i+1, err)
+		}
+		active[i] = expr.Eval(vars)
+	}
+
+	var removed []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if keep(m.Rules, active, rel) {
+			return nil
+		}
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+		removed = append(removed, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// compileCondition compila a condição de uma regra e verifica que ela só usa
+// entradas declaradas no manifesto ou variáveis embutidas, para que um erro de
+// digitação não torne a condição silenciosamente falsa
+func compileCondition(m *domain.TemplateManifest, when string) (*Expression, error) {
+	expr, err := CompileExpression(when)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range expr.Identifiers() {
+		if !declared(m, name) {
+			return nil, fmt.Errorf("unknown identifier %q in expression %q", name, when)
+		}
+	}
+	return expr, nil
+}
+
+// declared indica se name é uma entrada do manifesto ou uma variável embutida
+func declared(m *domain.TemplateManifest, name string) bool {
+	if name == ProjectNameVariable || name == TemplateNameVariable {
+		return true
+	}
+	for _, input := range m.Inputs {
+		if input.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// keep indica se o arquivo deve permanecer no projeto gerado
+func keep(rules []domain.TemplateRule, active []bool, rel string) bool {
+	for i, rule := range rules {
+		if !active[i] && matchAny(rule.Include, rel) {
+			return false
+		}
+		if active[i] && matchAny(rule.Exclude, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match verifica se um caminho relativo casa com um padrão glob. Além da sintaxe
+// de path.Match, "**" casa com qualquer quantidade de diretórios e um padrão
+// sem curingas também casa com todo o conteúdo do diretório de mesmo nome.
+func Match(pattern, rel string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return rel == pattern || strings.HasPrefix(rel, pattern+"/")
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
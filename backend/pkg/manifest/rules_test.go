@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"template-manager-backend/internal/domain"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"Dockerfile", "Dockerfile", true},
+		{"Dockerfile", "docker/Dockerfile", false},
+		{"frontend", "frontend/src/app.ts", true},
+		{"frontend/", "frontend/index.html", true},
+		{"frontend", "frontend-legacy/index.html", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide.md", false},
+		{"docs/*.md", "docs/guide.md", true},
+		{"docs/*.md", "docs/api/guide.md", false},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/api/guide.md", true},
+		{"docker/**", "docker/compose.yaml", true},
+		{"docker/**", "docker/postgres/init.sql", true},
+		{"docker/**", "dockerfile", false},
+		{"src/**/test_*.go", "src/test_main.go", true},
+		{"src/**/test_*.go", "src/a/b/test_main.go", true},
+		{"src/**/test_*.go", "src/a/b/main.go", false},
+		{"", "README.md", false},
+		{"[a-", "a", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	inputs := []domain.TemplateInput{
+		{Name: "use_docker", Type: domain.InputTypeBool},
+		{Name: "database", Type: domain.InputTypeChoice, Choices: []string{"postgres", "mysql"}},
+	}
+	files := []string{
+		"README.md",
+		"Dockerfile",
+		"docker/compose.yaml",
+		"docker/postgres/init.sql",
+		"docker/mysql/init.sql",
+		"docs/docker.md",
+		".git/config",
+	}
+	rules := []domain.TemplateRule{
+		// include: só permanece quando a condição é verdadeira
+		{Include: []string{"Dockerfile", "docker/**"}, When: "use_docker"},
+		// exclude: é removido quando a condição é verdadeira
+		{Exclude: []string{"docker/postgres/**"}, When: `database != "postgres"`},
+		{Exclude: []string{"docker/mysql/**"}, When: `database != "mysql"`},
+	}
+
+	tests := []struct {
+		name    string
+		vars    map[string]any
+		removed []string
+	}{
+		{
+			name:    "include inactive removes everything it matches",
+			vars:    map[string]any{"use_docker": false, "database": "postgres"},
+			removed: []string{"Dockerfile", "docker/compose.yaml", "docker/mysql/init.sql", "docker/postgres/init.sql"},
+		},
+		{
+			name:    "active exclude wins over active include",
+			vars:    map[string]any{"use_docker": true, "database": "postgres"},
+			removed: []string{"docker/mysql/init.sql"},
+		},
+		{
+			name:    "each exclude is evaluated on its own",
+			vars:    map[string]any{"use_docker": true, "database": "mysql"},
+			removed: []string{"docker/postgres/init.sql"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range files {
+				path := filepath.Join(dir, filepath.FromSlash(file))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			m := &domain.TemplateManifest{Inputs: inputs, Rules: rules}
+			removed, err := ApplyRules(dir, m, tt.vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(removed)
+			if !reflect.DeepEqual(removed, tt.removed) {
+				t.Fatalf("removed = %q, want %q", removed, tt.removed)
+			}
+			for _, file := range files {
+				_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file)))
+				wantRemoved := false
+				for _, r := range tt.removed {
+					wantRemoved = wantRemoved || r == file
+				}
+				if exists := err == nil; exists == wantRemoved {
+					t.Errorf("%s exists = %v, want %v", file, exists, !wantRemoved)
+				}
+			}
+		})
+	}
+}
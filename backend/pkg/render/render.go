@@ -0,0 +1,240 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// binarySniffLen é a quantidade de bytes inspecionada para detectar arquivos binários
+const binarySniffLen = 8000
+
+// placeholderPattern reconhece placeholders no formato {{ .Nome }} ou {{ nome }}
+var placeholderPattern = regexp.MustCompile(`\{\{\s*\.?([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// String substitui os placeholders conhecidos em s pelos valores de vars.
+// Placeholders sem valor correspondente são mantidos intactos, o que preserva
+// sintaxes de outras ferramentas (Helm, GitHub Actions, templates Go).
+func String(s string, vars map[string]any) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		key := placeholderPattern.FindStringSubmatch(match)[1]
+		value, ok := vars[key]
+		if !ok {
+			return match
+		}
+		return Format(value)
+	})
+}
+
+// Format retorna a representação textual de um valor de variável. Números
+// decodificados de JSON chegam como float64 e são escritos sem notação
+// exponencial, para que 2000000 não vire 2e+06.
+func Format(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
+
+// ErrPathCollision indica que dois arquivos seriam renderizados para o mesmo caminho
+var ErrPathCollision = errors.New("path collision")
+
+// IsBinary indica se o conteúdo aparenta ser binário (contém byte nulo no início)
+func IsBinary(data []byte) bool {
+	if len(data) > binarySniffLen {
+		data = data[:binarySniffLen]
+	}
+	return bytes.IndexByte(data, 0) != -1
+}
+
+// Directory renderiza o conteúdo de todos os arquivos de texto sob root.
+// Arquivos binários, links simbólicos e o diretório .git são ignorados.
+func Directory(root string, vars map[string]any) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return renderFile(path, vars)
+	})
+}
+
+// renderFile aplica a substituição em um único arquivo, preservando suas permissões
+func renderFile(path string, vars map[string]any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if IsBinary(data) {
+		return nil
+	}
+
+	rendered := String(string(data), vars)
+	if rendered == string(data) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(rendered), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Paths renomeia arquivos e diretórios sob root cujos nomes contêm placeholders.
+// Arquivos com algum segmento de caminho renderizado vazio são removidos e
+// caminhos que colidem após a renderização resultam em ErrPathCollision.
+func Paths(root string, vars map[string]any) error {
+	type move struct{ from, to string }
+
+	var moves []move
+	var skipped []string
+	targets := make(map[string]string)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		target, ok, err := renderPath(rel, vars)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			skipped = append(skipped, rel)
+			return nil
+		}
+		if other, exists := targets[target]; exists {
+			return fmt.Errorf("%w: %s and %s both render to %s", ErrPathCollision, other, rel, target)
+		}
+		targets[target] = rel
+		if target != rel {
+			moves = append(moves, move{from: rel, to: target})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 && len(skipped) == 0 {
+		return nil
+	}
+
+	for _, rel := range skipped {
+		if err := os.Remove(filepath.Join(root, rel)); err != nil {
+			return err
+		}
+	}
+
+	// Os arquivos passam por um diretório intermediário para que renomeações
+	// encadeadas (a -> b, b -> c) não sobrescrevam umas às outras
+	staging, err := os.MkdirTemp(root, ".render-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	for i, m := range moves {
+		if err := os.Rename(filepath.Join(root, m.from), filepath.Join(staging, fmt.Sprint(i))); err != nil {
+			return err
+		}
+	}
+	for i, m := range moves {
+		dest := filepath.Join(root, m.to)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(staging, fmt.Sprint(i)), dest); err != nil {
+			return err
+		}
+	}
+
+	return pruneEmptyDirs(root, staging)
+}
+
+// renderPath renderiza cada segmento de um caminho relativo. O retorno ok é
+// falso quando algum segmento com placeholder resulta vazio.
+func renderPath(rel string, vars map[string]any) (string, bool, error) {
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, segment := range segments {
+		if !placeholderPattern.MatchString(segment) {
+			continue
+		}
+		rendered := strings.TrimSpace(String(segment, vars))
+		if rendered == "" {
+			return "", false, nil
+		}
+		segments[i] = rendered
+	}
+
+	target := filepath.Clean(filepath.FromSlash(strings.Join(segments, "/")))
+	if target == "." || filepath.IsAbs(target) || target == ".." || strings.HasPrefix(target, ".."+string(filepath.Separator)) {
+		return "", false, fmt.Errorf("path %s renders outside of the project: %s", rel, target)
+	}
+	return target, true, nil
+}
+
+// pruneEmptyDirs remove os diretórios que ficaram vazios após as renomeações
+func pruneEmptyDirs(root, skip string) error {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if path == skip || d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Percorre do mais profundo para o mais raso
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			if err := os.Remove(dirs[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringFormatsJSONNumbersWithoutExponent(t *testing.T) {
+	// Variáveis gravadas com serializer:json voltam do banco como float64
+	var vars map[string]any
+	if err := json.Unmarshal([]byte(`{"big":2000000,"memory":1048576,"ratio":0.5}`), &vars); err != nil {
+		t.Fatal(err)
+	}
+
+	got := String("{{big}} {{ .memory }} {{ratio}}", vars)
+	if want := "2000000 1048576 0.5"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{float64(2000000), "2000000"},
+		{float64(1.25), "1.25"},
+		{42, "42"},
+		{true, "true"},
+		{"text", "text"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.value); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// writeTree cria os arquivos sob root, indexados pelo caminho separado por barras
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listTree retorna os arquivos e diretórios sob root, diretórios com barra final
+func listTree(t *testing.T, root string) []string {
+	t.Helper()
+	var entries []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			rel += "/"
+		}
+		entries = append(entries, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestPathsRendersSegments(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"cmd/{{project_slug}}/main.go":  "package main",
+		"internal/{{ .module }}/a.go":   "package core",
+		"{{project_slug}}.md":           "docs",
+		"static/{{unknown}}.txt":        "kept as is",
+		"optional/{{ feature }}/b.go":   "removed",
+		"optional/{{feature}}_extra.go": "only the placeholder is empty",
+	})
+
+	vars := map[string]any{"project_slug": "demo", "module": "core", "feature": "  "}
+	if err := Paths(root, vars); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"cmd/",
+		"cmd/demo/",
+		"cmd/demo/main.go",
+		"demo.md",
+		"internal/",
+		"internal/core/",
+		"internal/core/a.go",
+		"optional/",
+		"optional/_extra.go",
+		"static/",
+		"static/{{unknown}}.txt",
+	}
+	if got := listTree(t, root); !reflect.DeepEqual(got, want) {
+		t.Fatalf("tree = %q, want %q", got, want)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "cmd", "demo", "main.go"))
+	if err != nil || string(data) != "package main" {
+		t.Fatalf("moved file content = %q, %v", data, err)
+	}
+}
+
+func TestPathsEmptySegmentSkipsFile(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"docker/{{docker_file}}": "FROM scratch",
+		"README.md":              "readme",
+	})
+
+	if err := Paths(root, map[string]any{"docker_file": ""}); err != nil {
+		t.Fatal(err)
+	}
+	// O diretório que ficou vazio também é removido
+	if got := listTree(t, root); !reflect.DeepEqual(got, []string{"README.md"}) {
+		t.Fatalf("tree = %q, want only README.md", got)
+	}
+}
+
+func TestPathsCollision(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"{{name}}.go": "rendered",
+		"demo.go":     "existing",
+	})
+
+	err := Paths(root, map[string]any{"name": "demo"})
+	if !errors.Is(err, ErrPathCollision) {
+		t.Fatalf("Paths() error = %v, want ErrPathCollision", err)
+	}
+	// Nada é movido quando há colisão
+	if got := listTree(t, root); !reflect.DeepEqual(got, []string{"demo.go", "{{name}}.go"}) {
+		t.Fatalf("tree = %q, want it untouched", got)
+	}
+}
+
+func TestPathsRejectsTraversal(t *testing.T) {
+	for _, value := range []string{"..", "../escape", "a/../../escape"} {
+		root := t.TempDir()
+		writeTree(t, root, map[string]string{"{{dir}}/file.txt": "content"})
+
+		err := Paths(root, map[string]any{"dir": value})
+		if err == nil || !strings.Contains(err.Error(), "outside of the project") {
+			t.Errorf("Paths() with dir %q error = %v, want traversal rejected", value, err)
+		}
+		if _, statErr := os.Stat(filepath.Join(filepath.Dir(root), "escape")); statErr == nil {
+			t.Fatalf("dir %q wrote outside of the project", value)
+		}
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want bool
+	}{
+		{[]byte("package main\n"), false},
+		{[]byte{}, false},
+		{[]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), true},
+		{[]byte("{{name}}\x00"), true},
+	}
+	for _, tt := range tests {
+		if got := IsBinary(tt.data); got != tt.want {
+			t.Errorf("IsBinary(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestDirectoryCopiesBinaryFilesUnchanged(t *testing.T) {
+	root := t.TempDir()
+	// Um binário cujo conteúdo contém um placeholder e bytes que não são UTF-8
+	binary := "\x89PNG\r\n\x1a\n\x00\x00{{name}}\xff\xfe{{ .name }}\x00"
+	writeTree(t, root, map[string]string{
+		"README.md":       "# {{name}}\n",
+		"assets/logo.png": binary,
+		".git/HEAD":       "{{name}}",
+	})
+
+	if err := Directory(root, map[string]any{"name": "demo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"README.md":       "# demo\n",
+		"assets/logo.png": binary,
+		".git/HEAD":       "{{name}}",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+}
@@ -1,36 +1,74 @@
 "use client";
 
-import { useEffect, useState } from "react";
+import { useCallback, useEffect, useRef, useState } from "react";
 import { useParams } from "next/navigation";
 import Link from "next/link";
 import { apiClient } from "@/lib/api";
-import { Project } from "@/types";
+import { IN_PROGRESS_STATUSES, LogEvent, Project } from "@/types";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
 
 export default function ProjectDetailPage() {
   const params = useParams();
   const id = Number(params.id);
   const [project, setProject] = useState<Project | null>(null);
-  const [logs, setLogs] = useState<string[]>([]);
+  const [logs, setLogs] = useState<LogEvent[]>([]);
   const [error, setError] = useState<string | null>(null);
+  // Incrementado para reabrir o stream de logs após um retry
+  const [stream, setStream] = useState(0);
+  const lastSequence = useRef(0);
 
-  useEffect(() => {
-    const fetchProject = async () => {
-      try {
-        const data = await apiClient.getProject(id);
-        setProject(data);
-      } catch {
-        setError("Failed to fetch project");
-      }
-    };
+  const fetchProject = useCallback(async () => {
+    try {
+      const data = await apiClient.getProject(id);
+      setProject(data);
+    } catch {
+      setError("Failed to fetch project");
+    }
+  }, [id]);
 
+  useEffect(() => {
     fetchProject();
-    const es = apiClient.streamProjectLogs(id, (msg) => {
-      setLogs((prev) => [...prev, msg]);
-    });
+    const es = apiClient.streamProjectLogs(
+      id,
+      (event) => {
+        lastSequence.current = event.sequence;
+        setLogs((prev) => [...prev, event]);
+        // Mudanças de status atualizam as etapas exibidas no progresso
+        if (event.type === "status") fetchProject();
+      },
+      () => fetchProject(),
+      lastSequence.current,
+    );
     return () => es.close();
-  }, [id]);
+  }, [id, stream, fetchProject]);
+
+  const handleCancel = async () => {
+    try {
+      await apiClient.cancelProject(id);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to cancel project");
+    }
+  };
+
+  const handleRetry = async () => {
+    try {
+      const data = await apiClient.retryProject(id);
+      setProject(data);
+      setStream((prev) => prev + 1);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to retry project");
+    }
+  };
+
+  const steps = project?.steps ?? [];
+  const completedSteps = steps.filter(
+    (step) => step.status === "done" || step.status === "skipped",
+  ).length;
+  const progress = steps.length
+    ? Math.round((completedSteps / steps.length) * 100)
+    : 0;
 
   if (error) return <div className="text-red-600">{error}</div>;
   if (!project) return <div>Loading...</div>;
@@ -47,6 +85,22 @@ export default function ProjectDetailPage() {
         <CardContent className="space-y-2">
           <div className="flex items-center gap-2">
             <Badge>{project.status}</Badge>
+            {project.queue_position && (
+              <span className="text-sm text-gray-500 dark:text-gray-400">
+                Queue position: {project.queue_position}
+              </span>
+            )}
+            {IN_PROGRESS_STATUSES.includes(project.status) && (
+              <Button variant="outline" size="sm" onClick={handleCancel}>
+                Cancel
+              </Button>
+            )}
+            {(project.status === "error" ||
+              project.status === "cancelled") && (
+              <Button variant="outline" size="sm" onClick={handleRetry}>
+                Retry
+              </Button>
+            )}
             {project.git_url && (
               <a
                 href={project.git_url}
@@ -59,14 +113,74 @@ export default function ProjectDetailPage() {
             )}
           </div>
           <div className="text-sm">Template: {project.template?.name}</div>
+          {project.failed_step && (
+            <div className="text-sm text-red-600 dark:text-red-400">
+              Failed step: {project.failed_step}
+            </div>
+          )}
         </CardContent>
       </Card>
+      {steps.length > 0 && (
+        <Card>
+          <CardHeader>
+            <CardTitle>Progress</CardTitle>
+          </CardHeader>
+          <CardContent className="space-y-3">
+            <div className="h-2 w-full rounded bg-gray-200 dark:bg-gray-700">
+              <div
+                className="h-2 rounded bg-blue-600 transition-all"
+                style={{ width: `${progress}%` }}
+              />
+            </div>
+            <ul className="space-y-1 text-sm">
+              {steps.map((step) => (
+                <li key={step.id} className="flex items-center gap-2">
+                  <Badge variant="outline">{step.status}</Badge>
+                  <span>{step.name}</span>
+                  {step.error && (
+                    <span className="text-red-600 dark:text-red-400">
+                      {step.error}
+                    </span>
+                  )}
+                </li>
+              ))}
+            </ul>
+          </CardContent>
+        </Card>
+      )}
       <Card>
         <CardHeader>
           <CardTitle>Logs</CardTitle>
         </CardHeader>
         <CardContent>
-          <pre className="text-sm whitespace-pre-wrap">{logs.join("\n")}</pre>
+          <div className="space-y-1 font-mono text-sm">
+            {logs.map((event) => (
+              <div
+                key={event.sequence}
+                className={
+                  event.level === "error"
+                    ? "text-red-600 dark:text-red-400"
+                    : event.level === "warn"
+                      ? "text-yellow-600 dark:text-yellow-400"
+                      : event.type === "log"
+                        ? ""
+                        : "text-gray-500 dark:text-gray-400"
+                }
+              >
+                <span className="text-gray-400">
+                  {new Date(event.timestamp).toLocaleTimeString()}
+                </span>{" "}
+                {event.step && <span>[{event.step}] </span>}
+                {event.message}
+                {event.duration_ms !== undefined && (
+                  <span> ({(event.duration_ms / 1000).toFixed(1)}s)</span>
+                )}
+                {event.error && (
+                  <div className="whitespace-pre-wrap pl-4">{event.error}</div>
+                )}
+              </div>
+            ))}
+          </div>
         </CardContent>
       </Card>
     </div>
@@ -6,7 +6,7 @@ import Link from "next/link";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Badge } from "@/components/ui/badge";
 import { Skeleton } from "@/components/ui/skeleton";
-import { Project } from "@/types";
+import { IN_PROGRESS_STATUSES, Project, ProjectStatus } from "@/types";
 import { apiClient } from "@/lib/api";
 import {
   Trash2,
@@ -42,20 +42,61 @@ export function ProjectList({ refreshTrigger }: ProjectListProps) {
     fetchProjects();
   }, [refreshTrigger]);
 
-  const handleDelete = async (id: number) => {
+  // Acompanha o status das criações em andamento por um único WebSocket
+  const inProgressIds = projects
+    .filter((p) => IN_PROGRESS_STATUSES.includes(p.status))
+    .map((p) => p.id)
+    .join(",");
+  useEffect(() => {
+    if (!inProgressIds) return;
+    const ws = apiClient.openProjectEvents(
+      inProgressIds.split(",").map(Number),
+      async (message) => {
+        if (message.type === "status") {
+          const status = message.status as ProjectStatus;
+          setProjects((prev) =>
+            prev.map((p) =>
+              p.id === message.project_id ? { ...p, status } : p,
+            ),
+          );
+        } else if (message.type === "done") {
+          // Ao fim da criação o projeto também ganha a URL do repositório
+          const project = await apiClient.getProject(message.project_id);
+          setProjects((prev) =>
+            prev.map((p) => (p.id === project.id ? project : p)),
+          );
+        }
+      },
+    );
+    return () => ws.close();
+  }, [inProgressIds]);
+
+  const handleDelete = async (project: Project) => {
     if (!confirm("Are you sure you want to delete this project?")) return;
 
+    // O repositório remoto só é removido quando o nome do projeto é confirmado
+    const confirmation = project.git_url
+      ? prompt(
+          `Type "${project.name}" to also delete the remote repository, or leave empty to keep it.`,
+        )
+      : null;
+    const remote = confirmation ? "delete" : "keep";
+
     try {
-      await apiClient.deleteProject(id);
-      setProjects((prev) => prev.filter((p) => p.id !== id));
+      await apiClient.deleteProject(project.id, remote, confirmation || undefined);
+      setProjects((prev) => prev.filter((p) => p.id !== project.id));
     } catch (err) {
       setError(err instanceof Error ? err.message : "Failed to delete project");
     }
   };
 
-  const getStatusIcon = (status: string) => {
+  const getStatusIcon = (status: ProjectStatus) => {
     switch (status) {
-      case "creating":
+      case "queued":
+      case "cloning":
+      case "rendering":
+      case "creating_remote":
+      case "pushing":
         return (
           <Clock className="h-4 w-4 text-yellow-600 dark:text-yellow-400" />
         );
@@ -65,14 +106,22 @@ export function ProjectList({ refreshTrigger }: ProjectListProps) {
         );
       case "error":
         return <XCircle className="h-4 w-4 text-red-600 dark:text-red-400" />;
+      case "cancelled":
+        return (
+          <XCircle className="h-4 w-4 text-gray-500 dark:text-gray-400" />
+        );
       default:
         return null;
     }
   };
 
-  const getStatusVariant = (status: string) => {
+  const getStatusVariant = (status: ProjectStatus) => {
     switch (status) {
-      case "creating":
+      case "queued":
+      case "cloning":
+      case "rendering":
+      case "creating_remote":
+      case "pushing":
         return "secondary";
       case "ready":
         return "default";
@@ -140,7 +189,7 @@ export function ProjectList({ refreshTrigger }: ProjectListProps) {
               <Button
                 size="sm"
                 variant="ghost"
-                onClick={() => handleDelete(project.id)}
+                onClick={() => handleDelete(project)}
                 className="text-red-600 hover:text-red-700 dark:text-red-400 dark:hover:text-red-300"
               >
                 <Trash2 className="h-4 w-4" />
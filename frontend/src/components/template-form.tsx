@@ -5,8 +5,15 @@ import { Button } from '@/components/ui/button';
 import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
 import { Textarea } from '@/components/ui/textarea';
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select';
 import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
-import { CreateTemplateRequest, Template } from '@/types';
+import { CreateTemplateRequest, RollbackPolicy, Template } from '@/types';
 import { apiClient } from '@/lib/api';
 
 interface TemplateFormProps {
@@ -22,6 +29,7 @@ export function TemplateForm({ template, onSuccess, onCancel }: TemplateFormProp
     git_url: template?.git_url || '',
     language: template?.language || '',
     tags: template?.tags || '',
+    rollback_policy: template?.rollback_policy || 'delete',
   });
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
@@ -110,6 +118,25 @@ export function TemplateForm({ template, onSuccess, onCancel }: TemplateFormProp
             />
           </div>
 
+          <div className="space-y-2">
+            <Label htmlFor="rollback_policy">On failed generation</Label>
+            <Select
+              value={formData.rollback_policy}
+              onValueChange={(value) =>
+                handleChange('rollback_policy', value as RollbackPolicy)
+              }
+            >
+              <SelectTrigger id="rollback_policy">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="delete">Delete remote repository</SelectItem>
+                <SelectItem value="archive">Archive remote repository</SelectItem>
+                <SelectItem value="keep">Keep remote repository</SelectItem>
+              </SelectContent>
+            </Select>
+          </div>
+
           {error && (
             <div className="text-red-600 dark:text-red-400 text-sm">{error}</div>
           )}
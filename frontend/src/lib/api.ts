@@ -1,8 +1,16 @@
 import {
   Template,
+  TemplateInput,
   CreateTemplateRequest,
   Project,
   CreateProjectRequest,
+  RollbackPolicy,
+  LogEvent,
+  LogEventType,
+  ProjectStatus,
+  ProjectSocketMessage,
+  DomainEvent,
+  DomainEventType,
 } from "@/types";
 
 const API_BASE_URL =
@@ -59,6 +67,16 @@ class ApiClient {
     });
   }
 
+  async getTemplateInputs(id: number): Promise<TemplateInput[]> {
+    return this.request<TemplateInput[]>(`/templates/${id}/inputs`);
+  }
+
+  async refreshTemplate(id: number): Promise<Template> {
+    return this.request<Template>(`/templates/${id}/refresh`, {
+      method: "POST",
+    });
+  }
+
   async deleteTemplate(id: number): Promise<void> {
     return this.request<void>(`/templates/${id}`, {
       method: "DELETE",
@@ -81,18 +99,90 @@ class ApiClient {
     });
   }
 
-  async deleteProject(id: number): Promise<void> {
-    return this.request<void>(`/projects/${id}`, {
+  async deleteProject(
+    id: number,
+    remote: RollbackPolicy = "keep",
+    confirm?: string,
+  ): Promise<void> {
+    const params = new URLSearchParams({ remote });
+    if (confirm) params.set("confirm", confirm);
+    return this.request<void>(`/projects/${id}?${params}`, {
       method: "DELETE",
     });
   }
 
-  streamProjectLogs(id: number, onMessage: (msg: string) => void): EventSource {
-    const url = `${API_BASE_URL}/projects/${id}/logs`;
-    const ev = new EventSource(url);
-    ev.onmessage = (e) => {
-      onMessage(e.data);
+  async cancelProject(id: number): Promise<void> {
+    await this.request<{ message: string }>(`/projects/${id}/cancel`, {
+      method: "POST",
+    });
+  }
+
+  async retryProject(id: number): Promise<Project> {
+    return this.request<Project>(`/projects/${id}/retry`, {
+      method: "POST",
+    });
+  }
+
+  // Eventos de domínio de todos os recursos, para feeds de atividade
+  streamEvents(onEvent: (event: DomainEvent) => void): EventSource {
+    const ev = new EventSource(`${API_BASE_URL}/events`);
+    const types: DomainEventType[] = [
+      "template.created",
+      "template.updated",
+      "template.deleted",
+      "project.queued",
+      "project.status_changed",
+      "project.deleted",
+    ];
+    types.forEach((type) => {
+      ev.addEventListener(type, (e) => {
+        onEvent(JSON.parse((e as MessageEvent).data));
+      });
+    });
+    return ev;
+  }
+
+  // Um único WebSocket recebe os eventos de vários projetos; as inscrições
+  // são enviadas quando a conexão abre
+  openProjectEvents(
+    projectIds: number[],
+    onEvent: (event: ProjectSocketMessage) => void,
+  ): WebSocket {
+    const url = `${API_BASE_URL.replace(/^http/, "ws")}/projects/ws`;
+    const ws = new WebSocket(url);
+    ws.onopen = () => {
+      projectIds.forEach((id) =>
+        ws.send(JSON.stringify({ action: "subscribe", project_id: id })),
+      );
+    };
+    ws.onmessage = (e) => {
+      onEvent(JSON.parse(e.data));
     };
+    return ws;
+  }
+
+  // O navegador reconecta sozinho enviando Last-Event-ID; lastEventId retoma
+  // um stream já encerrado, como após um retry
+  streamProjectLogs(
+    id: number,
+    onEvent: (event: LogEvent) => void,
+    onDone?: (status: ProjectStatus) => void,
+    lastEventId?: number,
+  ): EventSource {
+    let url = `${API_BASE_URL}/projects/${id}/logs`;
+    if (lastEventId) url += `?last_event_id=${lastEventId}`;
+    const ev = new EventSource(url);
+    const types: LogEventType[] = ["log", "status", "step", "lagged"];
+    types.forEach((type) => {
+      ev.addEventListener(type, (e) => {
+        onEvent(JSON.parse((e as MessageEvent).data));
+      });
+    });
+    ev.addEventListener("done", (e) => {
+      // Sem close o EventSource reconectaria ao fim do stream
+      ev.close();
+      onDone?.(JSON.parse((e as MessageEvent).data).status);
+    });
     return ev;
   }
 }
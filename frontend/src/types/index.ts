@@ -1,20 +1,50 @@
+export type RollbackPolicy = 'delete' | 'archive' | 'keep';
+
 export interface Template {
   id: number;
   name: string;
   description: string;
   git_url: string;
+  ref: string;
+  subdirectory: string;
   language: string;
   tags: string;
+  rollback_policy: RollbackPolicy;
+  manifest?: TemplateManifest;
   created_at: string;
   updated_at: string;
 }
 
+export interface TemplateInput {
+  name: string;
+  type: 'string' | 'bool' | 'int' | 'choice';
+  default?: string | number | boolean;
+  description?: string;
+  regex?: string;
+  required: boolean;
+  choices?: string[];
+}
+
+export interface TemplateRule {
+  include?: string[];
+  exclude?: string[];
+  when: string;
+}
+
+export interface TemplateManifest {
+  inputs: TemplateInput[];
+  rules?: TemplateRule[];
+}
+
 export interface CreateTemplateRequest {
   name: string;
   description: string;
   git_url: string;
+  ref?: string;
+  subdirectory?: string;
   language: string;
   tags: string;
+  rollback_policy?: RollbackPolicy;
 }
 
 export interface Project {
@@ -23,12 +53,100 @@ export interface Project {
   git_url: string;
   template_id: number;
   template: Template;
-  status: 'creating' | 'ready' | 'error';
+  status: ProjectStatus;
+  provider: string;
+  template_ref: string;
+  template_commit: string;
+  failed_step?: string;
+  variables?: Record<string, string | number | boolean>;
+  steps?: ProjectStep[];
+  job?: ProjectJob;
+  queue_position?: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export type ProjectStatus =
+  | 'queued'
+  | 'cloning'
+  | 'rendering'
+  | 'creating_remote'
+  | 'pushing'
+  | 'ready'
+  | 'error'
+  | 'cancelled';
+
+export const IN_PROGRESS_STATUSES: ProjectStatus[] = [
+  'queued',
+  'cloning',
+  'rendering',
+  'creating_remote',
+  'pushing',
+];
+
+export interface ProjectStep {
+  id: number;
+  project_id: number;
+  name: 'clone' | 'clear_history' | 'render' | 'create_repository' | 'push';
+  status: 'pending' | 'running' | 'done' | 'failed' | 'skipped' | 'cancelled';
+  error?: string;
+  started_at?: string;
+  finished_at?: string;
+}
+
+export interface ProjectJob {
+  id: number;
+  project_id: number;
+  status: 'pending' | 'running' | 'done' | 'failed' | 'cancelled';
+  attempts: number;
+  last_error: string;
+  started_at?: string;
+  finished_at?: string;
   created_at: string;
   updated_at: string;
 }
 
+export type LogEventType = 'log' | 'status' | 'step' | 'lagged';
+
+export interface LogEvent {
+  project_id: number;
+  sequence: number;
+  type: LogEventType;
+  level: 'info' | 'warn' | 'error';
+  step?: string;
+  message: string;
+  error?: string;
+  status?: string;
+  duration_ms?: number;
+  timestamp: string;
+}
+
+// Mensagens do WebSocket de eventos de projetos: eventos de log ou controle
+export type ProjectSocketMessage =
+  | LogEvent
+  | { type: 'done'; project_id: number; status: ProjectStatus }
+  | { type: 'error'; project_id?: number; error: string };
+
+export type DomainEventType =
+  | 'template.created'
+  | 'template.updated'
+  | 'template.deleted'
+  | 'project.queued'
+  | 'project.status_changed'
+  | 'project.deleted';
+
+export interface DomainEvent {
+  id: number;
+  type: DomainEventType;
+  resource_id: number;
+  data?: unknown;
+  timestamp: string;
+}
+
 export interface CreateProjectRequest {
   name: string;
   template_id: number;
+  template_ref?: string;
+  provider?: string;
+  variables?: Record<string, string | number | boolean>;
 }